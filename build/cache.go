@@ -0,0 +1,118 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// Caching of parsed files.
+
+package build
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// defaultMaxCachedFiles is the size of a Cache whose MaxFiles is not set.
+const defaultMaxCachedFiles = 1000
+
+// A Cache stores parsed files keyed by their file names and the SHA-256 hashes of their contents.
+// When the cache is full, the least recently used files are evicted first.
+// The zero value is an empty cache ready to use. It's safe for concurrent use.
+type Cache struct {
+	// MaxFiles is the maximum number of files stored in the cache. If it's not positive,
+	// at most 1000 files are stored.
+	MaxFiles int
+
+	mu    sync.Mutex
+	files map[cacheKey]*list.Element
+	lru   *list.List // of *cacheEntry, the most recently used first
+}
+
+type cacheKey struct {
+	filename string // the file type depends on the file name
+	hash     [sha256.Size]byte
+}
+
+type cacheEntry struct {
+	key  cacheKey
+	file *File
+}
+
+func newCacheKey(filename string, data []byte) cacheKey {
+	return cacheKey{filename, sha256.Sum256(data)}
+}
+
+// Get returns a copy of the file previously stored for the file name and contents.
+// It returns nil if there's no such file in the cache.
+func (c *Cache) Get(filename string, data []byte) *File {
+	c.mu.Lock()
+	var f *File
+	if e, ok := c.files[newCacheKey(filename, data)]; ok {
+		c.lru.MoveToFront(e)
+		f = e.Value.(*cacheEntry).file
+	}
+	c.mu.Unlock()
+	if f == nil {
+		return nil
+	}
+	return Copy(f).(*File)
+}
+
+// Put stores a copy of the parsed file f for the file name and contents,
+// evicting the least recently used file if the cache is full.
+func (c *Cache) Put(filename string, data []byte, f *File) {
+	f = Copy(f).(*File)
+	key := newCacheKey(filename, data)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.files == nil {
+		c.files = make(map[cacheKey]*list.Element)
+		c.lru = list.New()
+	}
+	if e, ok := c.files[key]; ok {
+		e.Value.(*cacheEntry).file = f
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.files[key] = c.lru.PushFront(&cacheEntry{key, f})
+
+	maxFiles := c.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = defaultMaxCachedFiles
+	}
+	for c.lru.Len() > maxFiles {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.files, e.Value.(*cacheEntry).key)
+	}
+}
+
+// ParseCached is like Parse but returns the result from the cache if the same
+// contents have already been parsed with the same file name. The returned file
+// is never shared with the cache, it can be modified by the caller.
+// If cache is nil, ParseCached is equivalent to Parse.
+func ParseCached(cache *Cache, filename string, data []byte) (*File, error) {
+	if cache == nil {
+		return Parse(filename, data)
+	}
+	if f := cache.Get(filename, data); f != nil {
+		return f, nil
+	}
+	f, err := Parse(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	cache.Put(filename, data, f)
+	return f, nil
+}
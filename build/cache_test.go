@@ -0,0 +1,126 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package build
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseCached(t *testing.T) {
+	data := []byte(`cc_library(
+    name = "foo",  # comment
+    srcs = ["foo.cc"],
+)
+`)
+	cache := &Cache{}
+
+	if f := cache.Get("BUILD", data); f != nil {
+		t.Fatal("got a file from an empty cache")
+	}
+	f1, err := ParseCached(cache, "BUILD", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := cache.Get("BUILD", data); f == nil {
+		t.Fatal("the parsed file hasn't been cached")
+	}
+
+	// Same contents, different file name or type
+	if f := cache.Get("foo.bzl", data); f != nil {
+		t.Error("got a cached file for a different file name")
+	}
+	// Same file name, different contents
+	if f := cache.Get("BUILD", append(data, '\n')); f != nil {
+		t.Error("got a cached file for different contents")
+	}
+
+	f2, err := ParseCached(cache, "BUILD", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f1 == f2 {
+		t.Fatal("ParseCached returned the same file twice")
+	}
+	if !reflect.DeepEqual(f1, f2) {
+		t.Error("the cached file differs from the parsed one")
+	}
+
+	// Modifying a returned file must not affect the cache
+	f1.Rules("")[0].SetAttr("name", NewString("bar"))
+	f1.Stmt[0].(*CallExpr).List[0].Comment().Suffix[0].Token = "# changed"
+	f3, err := ParseCached(cache, "BUILD", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(Format(f3)); got != string(data) {
+		t.Errorf("the cached file has been modified:\n%s", got)
+	}
+}
+
+func TestParseCachedNilCache(t *testing.T) {
+	f, err := ParseCached(nil, "BUILD", []byte(`foo(name = "bar")`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Stmt) != 1 {
+		t.Errorf("got %d statements, want 1", len(f.Stmt))
+	}
+	if _, err := ParseCached(&Cache{}, "BUILD", []byte("foo(")); err == nil {
+		t.Error("got no error for invalid input")
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	cache := &Cache{MaxFiles: 2}
+	a, b, c := []byte(`a()`), []byte(`b()`), []byte(`c()`)
+	for _, data := range [][]byte{a, b} {
+		if _, err := ParseCached(cache, "BUILD", data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Make b the least recently used file
+	if f := cache.Get("BUILD", a); f == nil {
+		t.Fatal("the parsed file hasn't been cached")
+	}
+	if _, err := ParseCached(cache, "BUILD", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if f := cache.Get("BUILD", b); f != nil {
+		t.Error("the least recently used file hasn't been evicted")
+	}
+	for _, data := range [][]byte{a, c} {
+		if f := cache.Get("BUILD", data); f == nil {
+			t.Errorf("the file %q has been evicted", data)
+		}
+	}
+	if len(cache.files) != 2 || cache.lru.Len() != 2 {
+		t.Errorf("got %d files in the cache, want 2", len(cache.files))
+	}
+}
+
+func TestCacheDefaultSize(t *testing.T) {
+	cache := &Cache{}
+	for i := 0; i < defaultMaxCachedFiles+10; i++ {
+		cache.Put("BUILD", []byte(fmt.Sprintf("x = %d", i)), &File{})
+	}
+	if len(cache.files) != defaultMaxCachedFiles {
+		t.Errorf("got %d files in the cache, want %d", len(cache.files), defaultMaxCachedFiles)
+	}
+}
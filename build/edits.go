@@ -0,0 +1,246 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// Computation of minimal text edits produced by the formatter.
+
+package build
+
+import (
+	"bytes"
+)
+
+// A TextEdit replaces the bytes data[Start:End] of the original input with NewText.
+type TextEdit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// FormatEdits parses and formats the given file the same way as buildifier does (Rewrite
+// followed by Format) and returns the edits that transform the original data into the
+// formatted output. The edits work at the granularity of whole lines, they are sorted by
+// their offsets and don't overlap, so they can be applied in reverse order without
+// adjusting the offsets.
+func FormatEdits(filename string, data []byte) ([]TextEdit, error) {
+	f, err := Parse(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	Rewrite(f, nil)
+	return lineEdits(data, Format(f)), nil
+}
+
+// splitLines splits data into lines, each line keeps its trailing newline.
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n') + 1
+		if i == 0 {
+			i = len(data)
+		}
+		lines = append(lines, data[:i])
+		data = data[i:]
+	}
+	return lines
+}
+
+// lineEdits computes a line-based diff of old and new as a list of edits to old.
+func lineEdits(old, new []byte) []TextEdit {
+	a, b := splitLines(old), splitLines(new)
+	d := newLineDiff(a, b)
+	d.diff(0, len(a), 0, len(b))
+
+	var edits []TextEdit
+	var edit *TextEdit
+	flush := func() {
+		if edit != nil {
+			edits = append(edits, *edit)
+			edit = nil
+		}
+	}
+	offset := 0
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && !d.keptA[i]:
+			// Delete a line of the old text
+			if edit == nil {
+				edit = &TextEdit{Start: offset, End: offset}
+			}
+			offset += len(a[i])
+			edit.End = offset
+			i++
+		case j < len(b) && !d.keptB[j]:
+			// Insert a line of the new text
+			if edit == nil {
+				edit = &TextEdit{Start: offset, End: offset}
+			}
+			edit.NewText += string(b[j])
+			j++
+		default:
+			// The common lines of old and new are kept in the same order.
+			flush()
+			offset += len(a[i])
+			i++
+			j++
+		}
+	}
+	flush()
+	return edits
+}
+
+// A lineDiff finds the longest common subsequence of two lists of lines with the
+// linear space variant of Myers' algorithm, see "An O(ND) Difference Algorithm and
+// Its Variations". It runs in O((N+M)D) time and O(N+M) space, where D is the number
+// of changed lines, so large files that are mostly unchanged are cheap to compare.
+// If the lines differ too much, the result may be longer than the minimal diff.
+type lineDiff struct {
+	a, b         []int  // the lines, represented by unique numbers
+	keptA, keptB []bool // whether each line belongs to the common subsequence
+}
+
+func newLineDiff(a, b [][]byte) *lineDiff {
+	ids := make(map[string]int)
+	number := func(lines [][]byte) []int {
+		result := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[string(line)]
+			if !ok {
+				id = len(ids)
+				ids[string(line)] = id
+			}
+			result[i] = id
+		}
+		return result
+	}
+	return &lineDiff{
+		a:     number(a),
+		b:     number(b),
+		keptA: make([]bool, len(a)),
+		keptB: make([]bool, len(b)),
+	}
+}
+
+// diff marks the common lines of a[a0:a1] and b[b0:b1].
+func (d *lineDiff) diff(a0, a1, b0, b1 int) {
+	// Common prefix and suffix don't need to be diffed, in practice that's most of the file.
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.keptA[a0], d.keptB[b0] = true, true
+		a0++
+		b0++
+	}
+	for a0 < a1 && b0 < b1 && d.a[a1-1] == d.b[b1-1] {
+		a1--
+		b1--
+		d.keptA[a1], d.keptB[b1] = true, true
+	}
+	if a0 == a1 || b0 == b1 {
+		return
+	}
+	if x, y, ok := d.middleSnake(a0, a1, b0, b1); ok {
+		d.diff(a0, x, b0, y)
+		d.diff(x, a1, y, b1)
+	}
+}
+
+// maxDiffSteps limits the number of steps of a single search for a middle snake. Beyond
+// that the ranges are split in the middle, which is fast but may miss common lines.
+const maxDiffSteps = 1000
+
+// middleSnake finds a point (x, y) on an optimal path from (a0, b0) to (a1, b1) by searching
+// forwards from the start and backwards from the end at the same time. If that takes more
+// than maxDiffSteps steps, a point in the middle of the ranges is returned instead. Returns
+// false if the ranges have no lines in common.
+func (d *lineDiff) middleSnake(a0, a1, b0, b1 int) (x, y int, ok bool) {
+	n, m := a1-a0, b1-b0
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// v1[offset+k] and v2[offset+k] are the furthest x reached on the diagonal k
+	// by the forward and the backward search, or -1.
+	v1 := make([]int, 2*maxD+2)
+	v2 := make([]int, 2*maxD+2)
+	for i := range v1 {
+		v1[i], v2[i] = -1, -1
+	}
+	v1[offset+1], v2[offset+1] = 0, 0
+	delta := n - m
+	// If delta is odd the paths overlap after a forward step, otherwise after a backward one.
+	front := delta%2 != 0
+	// Diagonals that have run out of one of the ranges are skipped.
+	k1start, k1end, k2start, k2end := 0, 0, 0, 0
+	for step := 0; step < maxD; step++ {
+		if step > maxDiffSteps {
+			// Both ranges are split into non-empty parts, so that the recursion makes progress.
+			return a0 + (n+1)/2, b0 + m/2, true
+		}
+		for k1 := -step + k1start; k1 <= step-k1end; k1 += 2 {
+			k1Offset := offset + k1
+			var x1 int
+			if k1 == -step || (k1 != step && v1[k1Offset-1] < v1[k1Offset+1]) {
+				x1 = v1[k1Offset+1]
+			} else {
+				x1 = v1[k1Offset-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && d.a[a0+x1] == d.b[b0+y1] {
+				x1++
+				y1++
+			}
+			v1[k1Offset] = x1
+			switch {
+			case x1 > n:
+				k1end += 2
+			case y1 > m:
+				k1start += 2
+			case front:
+				k2Offset := offset + delta - k1
+				if k2Offset >= 0 && k2Offset < len(v2) && v2[k2Offset] != -1 && x1 >= n-v2[k2Offset] {
+					return a0 + x1, b0 + y1, true
+				}
+			}
+		}
+		for k2 := -step + k2start; k2 <= step-k2end; k2 += 2 {
+			k2Offset := offset + k2
+			var x2 int
+			if k2 == -step || (k2 != step && v2[k2Offset-1] < v2[k2Offset+1]) {
+				x2 = v2[k2Offset+1]
+			} else {
+				x2 = v2[k2Offset-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && d.a[a1-x2-1] == d.b[b1-y2-1] {
+				x2++
+				y2++
+			}
+			v2[k2Offset] = x2
+			switch {
+			case x2 > n:
+				k2end += 2
+			case y2 > m:
+				k2start += 2
+			case !front:
+				k1Offset := offset + delta - k2
+				if k1Offset >= 0 && k1Offset < len(v1) && v1[k1Offset] != -1 {
+					x1 := v1[k1Offset]
+					y1 := x1 - (k1Offset - offset)
+					if x1 >= n-x2 {
+						return a0 + x1, b0 + y1, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package build
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// applyEdits applies the edits, which must be sorted and not overlap, to data.
+func applyEdits(data []byte, edits []TextEdit) []byte {
+	var result []byte
+	last := 0
+	for _, e := range edits {
+		result = append(result, data[last:e.Start]...)
+		result = append(result, e.NewText...)
+		last = e.End
+	}
+	return append(result, data[last:]...)
+}
+
+func TestFormatEdits(t *testing.T) {
+	tests := []struct {
+		input string
+		edits []TextEdit
+	}{
+		{
+			input: `cc_library(
+    name = "foo",
+)
+`,
+			edits: nil,
+		},
+		{
+			input: `cc_library(name = 'foo')
+cc_library(
+    name = "bar",
+)
+`,
+			edits: []TextEdit{
+				{Start: 0, End: 25, NewText: "cc_library(name = \"foo\")\n\n"},
+			},
+		},
+		{
+			input: `load(":a.bzl", "a")
+cc_library(
+    name = "foo",
+    srcs = ["b.cc", "a.cc"],
+)
+
+
+cc_library(
+    name = "bar",
+)`,
+			edits: []TextEdit{
+				{Start: 20, End: 20, NewText: "\n"},
+				{Start: 50, End: 79, NewText: "    srcs = [\n        \"a.cc\",\n        \"b.cc\",\n    ],\n"},
+				{Start: 81, End: 82, NewText: ""},
+				{Start: 113, End: 114, NewText: ")\n"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		edits, err := FormatEdits("BUILD", []byte(tt.input))
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(edits, tt.edits) {
+			t.Errorf("#%d: got edits %#v, want %#v", i, edits, tt.edits)
+		}
+
+		f, err := ParseBuild("BUILD", []byte(tt.input))
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		Rewrite(f, nil)
+		want := Format(f)
+		if got := applyEdits([]byte(tt.input), edits); string(got) != string(want) {
+			t.Errorf("#%d: applying the edits gives\n%s\nwant\n%s", i, got, want)
+		}
+	}
+}
+
+func TestFormatEditsSyntaxError(t *testing.T) {
+	if _, err := FormatEdits("BUILD", []byte("cc_library(")); err == nil {
+		t.Error("got no error for invalid input")
+	}
+}
+
+func TestLineEdits(t *testing.T) {
+	tests := []struct {
+		old, new string
+		changed  int // number of deleted and inserted lines
+	}{
+		{"", "", 0},
+		{"a\n", "", 1},
+		{"", "a\n", 1},
+		{"a\nb\nc\n", "a\nb\nc\n", 0},
+		{"a\nb\nc\n", "a\nc\n", 1},
+		{"a\nb\nc\na\nb\nb\na\n", "c\nb\na\nb\na\nc\n", 5},
+		{"x\na\ny\nb\nz\n", "a\nb\n", 3},
+		{"a\nb", "a\nb\n", 2},
+	}
+	for i, tt := range tests {
+		edits := lineEdits([]byte(tt.old), []byte(tt.new))
+		if got := string(applyEdits([]byte(tt.old), edits)); got != tt.new {
+			t.Errorf("#%d: applying the edits gives %q, want %q", i, got, tt.new)
+		}
+		changed := 0
+		for _, e := range edits {
+			changed += len(splitLines([]byte(tt.old[e.Start:e.End]))) + len(splitLines([]byte(e.NewText)))
+		}
+		if changed != tt.changed {
+			t.Errorf("#%d: got %d changed lines, want %d", i, changed, tt.changed)
+		}
+	}
+}
+
+func TestLineEditsLargeInput(t *testing.T) {
+	// Every other line changes, a quadratic table for 100000 lines wouldn't fit in memory.
+	var old, new strings.Builder
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&old, "line %d\n", i)
+		if i%2 == 0 {
+			fmt.Fprintf(&new, "line %d\n", i)
+		} else {
+			fmt.Fprintf(&new, "changed %d\n", i)
+		}
+	}
+	edits := lineEdits([]byte(old.String()), []byte(new.String()))
+	if got := string(applyEdits([]byte(old.String()), edits)); got != new.String() {
+		t.Error("applying the edits doesn't give the new text")
+	}
+}
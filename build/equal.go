@@ -0,0 +1,192 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// Structural comparison and copying of syntax trees.
+
+package build
+
+import (
+	"reflect"
+)
+
+var (
+	positionType       = reflect.TypeOf(Position{})
+	commentsStructType = reflect.TypeOf(Comments{})
+	commentBlockType   = reflect.TypeOf(&CommentBlock{})
+	stringStructType   = reflect.TypeOf(StringExpr{})
+)
+
+// Equal reports whether f and g have the same syntax tree, including comments.
+// Positions of the nodes, layout hints such as ForceMultiLine or the quoting of strings
+// and the file paths are ignored, so a file is equal to the result of parsing its
+// formatted form.
+func (f *File) Equal(g *File) bool {
+	return equalFiles(f, g, false)
+}
+
+// EqualIgnoringComments is like Equal but also ignores all comments,
+// including standalone comment blocks.
+func (f *File) EqualIgnoringComments(g *File) bool {
+	return equalFiles(f, g, true)
+}
+
+// Equal reports whether the expressions x and y have the same syntax tree. Same as for
+// File.Equal, positions and layout hints are ignored, and so are the comments.
+func Equal(x, y Expr) bool {
+	if f, ok := x.(*File); ok {
+		if g, ok := y.(*File); ok {
+			return equalFiles(f, g, true)
+		}
+	}
+	return equalValues(reflect.ValueOf(&x).Elem(), reflect.ValueOf(&y).Elem(), true)
+}
+
+func equalFiles(f, g *File, ignoreComments bool) bool {
+	if f == nil || g == nil {
+		return f == g
+	}
+	if f.Type != g.Type {
+		return false
+	}
+	if !ignoreComments && !equalValues(reflect.ValueOf(f.Comments), reflect.ValueOf(g.Comments), false) {
+		return false
+	}
+	return equalValues(reflect.ValueOf(f.Stmt), reflect.ValueOf(g.Stmt), ignoreComments)
+}
+
+// equalValues compares two values of syntax tree nodes recursively,
+// skipping positions, layout hints and, if ignoreComments is set, comments.
+func equalValues(x, y reflect.Value, ignoreComments bool) bool {
+	if x.Type() != y.Type() {
+		return false
+	}
+	switch x.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if x.IsNil() || y.IsNil() {
+			return x.IsNil() == y.IsNil()
+		}
+		return equalValues(x.Elem(), y.Elem(), ignoreComments)
+
+	case reflect.Struct:
+		if x.Type() == positionType || (ignoreComments && x.Type() == commentsStructType) {
+			return true
+		}
+		for i := 0; i < x.NumField(); i++ {
+			if x.Type().Field(i).PkgPath != "" {
+				// Unexported fields such as File annotations aren't part of the syntax tree
+				continue
+			}
+			name := x.Type().Field(i).Name
+			if name == "ForceCompact" || name == "ForceMultiLine" || (x.Type() == stringStructType && (name == "Token" || name == "TripleQuote")) {
+				continue
+			}
+			if !equalValues(x.Field(i), y.Field(i), ignoreComments) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Slice:
+		if ignoreComments {
+			x, y = withoutCommentBlocks(x), withoutCommentBlocks(y)
+		}
+		if x.Len() != y.Len() {
+			return false
+		}
+		for i := 0; i < x.Len(); i++ {
+			if !equalValues(x.Index(i), y.Index(i), ignoreComments) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return x.Interface() == y.Interface()
+	}
+}
+
+// withoutCommentBlocks returns a copy of a slice of expressions without the *CommentBlock elements.
+// Other slices are returned unchanged.
+func withoutCommentBlocks(v reflect.Value) reflect.Value {
+	if v.Type().Elem().Kind() != reflect.Interface {
+		return v
+	}
+	result := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if elem := v.Index(i); elem.IsNil() || elem.Elem().Type() != commentBlockType {
+			result = reflect.Append(result, elem)
+		}
+	}
+	return result
+}
+
+// Copy returns a deep copy of the syntax tree x, including comments and positions
+// but not annotations.
+// The copy shares no nodes with the original, so either of them can be modified safely.
+func Copy(x Expr) Expr {
+	if x == nil {
+		return nil
+	}
+	c := copyValue(reflect.ValueOf(x)).Interface().(Expr)
+	if f, ok := x.(*File); ok {
+		// Trailing commas are identified by positions, which are preserved by the copy.
+		c.(*File).trailingCommas = f.trailingCommas
+	}
+	return c
+}
+
+// copyValue returns a deep copy of a value of a syntax tree node.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(copyValue(v.Elem()))
+		return c
+
+	case reflect.Interface:
+		c := reflect.New(v.Type()).Elem()
+		if !v.IsNil() {
+			c.Set(copyValue(v.Elem()))
+		}
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if !c.Field(i).CanSet() {
+				// Unexported fields such as File annotations refer to the original nodes
+				continue
+			}
+			c.Field(i).Set(copyValue(v.Field(i)))
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+
+	default:
+		return v
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package build
+
+import (
+	"testing"
+)
+
+const equalTestInput = `# File comment
+
+load(":foo.bzl", "foo")
+
+foo(
+    name = "bar",  # the name
+    srcs = ["bar.go"],
+)
+`
+
+func TestFileEqual(t *testing.T) {
+	tests := []struct {
+		name                 string
+		input                string
+		equal                bool
+		equalIgnoringComment bool
+	}{
+		{
+			name:                 "reparse",
+			input:                equalTestInput,
+			equal:                true,
+			equalIgnoringComment: true,
+		},
+		{
+			name: "different positions",
+			input: `# File comment
+
+load(":foo.bzl", "foo")
+foo(name = "bar",  # the name
+    srcs = ["bar.go"])
+`,
+			equal:                true,
+			equalIgnoringComment: true,
+		},
+		{
+			name: "changed comments",
+			input: `# Another file comment
+
+load(":foo.bzl", "foo")
+
+# A new comment
+foo(
+    name = "bar",
+    srcs = ["bar.go"],
+)
+`,
+			equal:                false,
+			equalIgnoringComment: true,
+		},
+		{
+			name: "changed attribute",
+			input: `# File comment
+
+load(":foo.bzl", "foo")
+
+foo(
+    name = "bar",  # the name
+    srcs = ["baz.go"],
+)
+`,
+			equal:                false,
+			equalIgnoringComment: false,
+		},
+	}
+
+	f, err := ParseBuild("BUILD", []byte(equalTestInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		g, err := ParseBuild("other/BUILD", []byte(tt.input))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := f.Equal(g); got != tt.equal {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.equal)
+		}
+		if got := f.EqualIgnoringComments(g); got != tt.equalIgnoringComment {
+			t.Errorf("%s: EqualIgnoringComments() = %v, want %v", tt.name, got, tt.equalIgnoringComment)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		x, y  string
+		equal bool
+	}{
+		{`True`, `True`, true},
+		{`True`, `False`, false},
+		{`"foo"`, `'foo'`, true},
+		{`"foo"`, `"""foo"""`, true},
+		{`"foo"`, `foo`, false},
+		{`["a", "b"]`, `[  # comment
+    "a",
+    "b",
+]`, true},
+		{`["a", "b"]`, `["b", "a"]`, false},
+		{`x + [1]`, `x+[1]`, true},
+	}
+	for _, tt := range tests {
+		x, err := ParseBzl("x.bzl", []byte(tt.x))
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := ParseBzl("y.bzl", []byte(tt.y))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Equal(x.Stmt[0], y.Stmt[0]); got != tt.equal {
+			t.Errorf("Equal(%s, %s) = %v, want %v", tt.x, tt.y, got, tt.equal)
+		}
+	}
+	if !Equal(nil, nil) || Equal(nil, &Ident{Name: "x"}) {
+		t.Error("Equal() doesn't handle nil expressions")
+	}
+
+	// Files are compared like with EqualIgnoringComments
+	f, err := ParseBuild("a/BUILD", []byte("foo(name = \"x\")  # comment\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := ParseBuild("b/BUILD", []byte("foo(\n    name = \"x\",\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := ParseBuild("a/BUILD", []byte("foo(name = \"y\")\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(f, g) {
+		t.Error("Equal() = false for files with the same syntax tree")
+	}
+	if Equal(f, h) {
+		t.Error("Equal() = true for files with different syntax trees")
+	}
+	if Equal(f, f.Stmt[0]) {
+		t.Error("Equal() = true for a file and an expression")
+	}
+}
+
+func TestCopy(t *testing.T) {
+	f, err := ParseBuild("BUILD", []byte(equalTestInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := Copy(f).(*File)
+	if !f.Equal(g) {
+		t.Fatal("the copy isn't equal to the original")
+	}
+	g.Rules("foo")[0].SetAttr("srcs", NewList())
+	if f.Equal(g) {
+		t.Error("modifying the copy has changed the original")
+	}
+	if Copy(nil) != nil {
+		t.Error("Copy(nil) != nil")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// Checking that formatting is idempotent.
+
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// CheckIdempotent formats the contents of a file the way buildifier does (Rewrite followed by
+// Format) and checks that formatting the result once more doesn't change it. It's intended for
+// tests of tools that generate BUILD or .bzl files. A non-nil error means that either the input
+// or the formatted output can't be parsed, or the second pass produced a different output, in
+// which case the error names the first line that differs.
+func CheckIdempotent(filename string, src []byte) error {
+	first, err := rewriteAndFormat(filename, src)
+	if err != nil {
+		return err
+	}
+	second, err := rewriteAndFormat(filename, first)
+	if err != nil {
+		return fmt.Errorf("can't parse the formatted output: %v", err)
+	}
+	if bytes.Equal(first, second) {
+		return nil
+	}
+	line, a, b := firstDifferentLine(first, second)
+	return fmt.Errorf("%s:%d: formatting isn't idempotent, %q is reformatted as %q", filename, line, a, b)
+}
+
+// firstDifferentLine returns the number and the contents of the first line that differs
+// between x and y, which must not be equal.
+func firstDifferentLine(x, y []byte) (line int, lineX, lineY string) {
+	linesX := strings.Split(string(x), "\n")
+	linesY := strings.Split(string(y), "\n")
+	for i := 0; ; i++ {
+		lineX, lineY = "", ""
+		if i < len(linesX) {
+			lineX = linesX[i]
+		}
+		if i < len(linesY) {
+			lineY = linesY[i]
+		}
+		if lineX != lineY || i >= len(linesX) || i >= len(linesY) {
+			return i + 1, lineX, lineY
+		}
+	}
+}
+
+func rewriteAndFormat(filename string, src []byte) ([]byte, error) {
+	f, err := Parse(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	Rewrite(f, nil)
+	return Format(f), nil
+}
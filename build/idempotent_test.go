@@ -0,0 +1,58 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package build
+
+import (
+	"testing"
+)
+
+func TestCheckIdempotent(t *testing.T) {
+	// The formatter is expected to be idempotent for all valid inputs, so there's no known input
+	// for which the second pass differs; only the success and the parse error cases can be tested.
+	for _, tt := range []struct {
+		filename, src string
+	}{
+		{"BUILD", "cc_library(name='lib',srcs=['b.cc','a.cc'],deps=[':b',\n# comment\n':a'])\n"},
+		{"defs.bzl", "def f(x,y):\n  return [x,\n y]\n"},
+		{"BUILD", "cc_library(\n    name = \"lib\",\n)\n"},
+	} {
+		if err := CheckIdempotent(tt.filename, []byte(tt.src)); err != nil {
+			t.Errorf("CheckIdempotent(%q, %q) = %v, want nil", tt.filename, tt.src, err)
+		}
+	}
+
+	if err := CheckIdempotent("BUILD", []byte("cc_library(name = \n")); err == nil {
+		t.Error("CheckIdempotent() = nil for a syntax error, want an error")
+	}
+}
+
+func TestFirstDifferentLine(t *testing.T) {
+	for _, tt := range []struct {
+		x, y         string
+		line         int
+		lineX, lineY string
+	}{
+		{"a\nb\nc\n", "a\nB\nc\n", 2, "b", "B"},
+		{"a\n", "a\n\n", 3, "", ""},
+		{"a\nb", "a", 2, "b", ""},
+	} {
+		line, lineX, lineY := firstDifferentLine([]byte(tt.x), []byte(tt.y))
+		if line != tt.line || lineX != tt.lineX || lineY != tt.lineY {
+			t.Errorf("firstDifferentLine(%q, %q) = %d, %q, %q, want %d, %q, %q", tt.x, tt.y, line, lineX, lineY, tt.line, tt.lineX, tt.lineY)
+		}
+	}
+}
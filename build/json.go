@@ -0,0 +1,178 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// JSON serialization of syntax trees.
+
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// The JSON schema mirrors the Go types of the syntax tree:
+//
+//   * Every syntax node (including the file itself) is an object with the key "Node" set to
+//     the name of its Go type (e.g. "CallExpr") and a key for each exported field of the type,
+//     e.g. {"Node": "Ident", "NamePos": {...}, "Name": "foo", "Comments": {...}}.
+//   * Embedded structs (Comments, Function) are objects under the name of their type.
+//   * Positions are objects with the keys "Line", "LineRune" and "Byte".
+//   * Lists are arrays, missing nodes and nil lists are null.
+
+// nodeKey is the JSON key that holds the type of a syntax node.
+const nodeKey = "Node"
+
+// nodeTypes maps the names of the syntax node types to the types.
+var nodeTypes = make(map[string]reflect.Type)
+
+func init() {
+	for _, node := range []Expr{
+		&File{}, &CommentBlock{}, &Ident{}, &BranchStmt{}, &LiteralExpr{}, &StringExpr{},
+		&CallExpr{}, &DotExpr{}, &Comprehension{}, &ForClause{}, &IfClause{}, &KeyValueExpr{},
+		&DictExpr{}, &ListExpr{}, &SetExpr{}, &TupleExpr{}, &UnaryExpr{}, &BinaryExpr{},
+		&AssignExpr{}, &ParenExpr{}, &SliceExpr{}, &IndexExpr{}, &LambdaExpr{},
+		&ConditionalExpr{}, &LoadStmt{}, &DefStmt{}, &ReturnStmt{}, &AssertStmt{}, &ForStmt{},
+		&IfStmt{},
+	} {
+		t := reflect.TypeOf(node).Elem()
+		nodeTypes[t.Name()] = t
+	}
+}
+
+// MarshalAST serializes the syntax tree of a file to JSON, see the schema above.
+// Annotations and other unexported information are not serialized.
+func MarshalAST(f *File) ([]byte, error) {
+	return json.Marshal(encodeJSON(reflect.ValueOf(f)))
+}
+
+// UnmarshalAST reconstructs a syntax tree serialized by MarshalAST.
+func UnmarshalAST(data []byte) (*File, error) {
+	var f *File
+	if err := decodeJSON(data, reflect.ValueOf(&f).Elem()); err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("no file in the JSON data")
+	}
+	return f, nil
+}
+
+// encodeJSON converts a value of the syntax tree to a value that can be serialized with encoding/json.
+func encodeJSON(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+			m := encodeJSONStruct(v.Elem())
+			if _, ok := nodeTypes[v.Elem().Type().Name()]; ok {
+				m[nodeKey] = v.Elem().Type().Name()
+			}
+			return m
+		}
+		return encodeJSON(v.Elem())
+	case reflect.Struct:
+		return encodeJSONStruct(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = encodeJSON(v.Index(i))
+		}
+		return list
+	}
+	return v.Interface()
+}
+
+func encodeJSONStruct(v reflect.Value) map[string]interface{} {
+	m := make(map[string]interface{})
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		m[field.Name] = encodeJSON(v.Field(i))
+	}
+	return m
+}
+
+// decodeJSON deserializes the JSON data into v, which must be settable.
+func decodeJSON(data []byte, v reflect.Value) error {
+	if string(data) == "null" {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		var node struct {
+			Node string
+		}
+		if err := json.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		t, ok := nodeTypes[node.Node]
+		if !ok {
+			return fmt.Errorf("unknown syntax node type %q", node.Node)
+		}
+		ptr := reflect.New(t)
+		if !ptr.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("syntax node type %q can't be used as %s", node.Node, v.Type())
+		}
+		if err := decodeJSON(data, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	case reflect.Ptr:
+		ptr := reflect.New(v.Type().Elem())
+		if err := decodeJSON(data, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if raw, ok := fields[field.Name]; ok && field.PkgPath == "" {
+				if err := decodeJSON(raw, v.Field(i)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeJSON(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface())
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package build
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/bazelbuild/buildtools/testutils"
+)
+
+// Test that the golden files are formatted identically after a JSON round trip.
+func TestMarshalASTRoundTrip(t *testing.T) {
+	outs, chdir := findTests(t, ".golden")
+	defer chdir()
+	for _, out := range outs {
+		data, err := ioutil.ReadFile(out)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		f, err := Parse(out, data)
+		if err != nil {
+			t.Errorf("%s: %v", out, err)
+			continue
+		}
+		js, err := MarshalAST(f)
+		if err != nil {
+			t.Errorf("%s: MarshalAST: %v", out, err)
+			continue
+		}
+		f2, err := UnmarshalAST(js)
+		if err != nil {
+			t.Errorf("%s: UnmarshalAST: %v", out, err)
+			continue
+		}
+		if want, got := Format(f), Format(f2); string(want) != string(got) {
+			t.Errorf("%s: formatting changed after a JSON round trip", out)
+			testutils.Tdiff(t, want, got)
+		}
+	}
+}
+
+func TestMarshalASTSchema(t *testing.T) {
+	f, err := ParseBuild("BUILD", []byte(`foo(name = "x")  # comment
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalAST(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file struct {
+		Node string
+		Path string
+		Stmt []struct {
+			Node     string
+			X        struct{ Node, Name string }
+			Comments struct{ Suffix []struct{ Token string } }
+		}
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	if file.Node != "File" || file.Path != "BUILD" || len(file.Stmt) != 1 {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+	call := file.Stmt[0]
+	if call.Node != "CallExpr" || call.X.Node != "Ident" || call.X.Name != "foo" {
+		t.Errorf("unexpected JSON for the call: %s", data)
+	}
+	if len(call.Comments.Suffix) != 1 || call.Comments.Suffix[0].Token != "# comment" {
+		t.Errorf("unexpected JSON for the comments: %s", data)
+	}
+
+	if _, err := UnmarshalAST([]byte(`{"Node": "File", "Stmt": [{"Node": "Unknown"}]}`)); err == nil {
+		t.Error("UnmarshalAST() succeeded for an unknown node type")
+	}
+}
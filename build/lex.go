@@ -146,6 +146,87 @@ func Parse(filename string, data []byte) (*File, error) {
 	return ParseDefault(filename, data)
 }
 
+// ParseRecover parses the input data like Parse, but doesn't stop at the first syntax error.
+// Instead, top-level statements that can't be parsed are skipped, and the parse tree of the
+// rest of the file is returned together with the list of errors. The positions of the nodes
+// in the returned tree correspond to the original data. It's intended for tools that need
+// a best-effort view of incomplete files, e.g. editors.
+func ParseRecover(filename string, data []byte) (*File, []ParseError) {
+	buf := append([]byte{}, data...)
+	var errs []ParseError
+	for {
+		f, err := Parse(filename, buf)
+		if err == nil {
+			return f, errs
+		}
+		perr, ok := err.(ParseError)
+		if !ok {
+			perr = ParseError{Message: err.Error(), Filename: filename}
+		}
+		errs = append(errs, perr)
+		if !blankStatement(buf, perr.Pos.Line) {
+			// Nothing left to remove.
+			f, _ := Parse(filename, nil)
+			return f, errs
+		}
+	}
+}
+
+// blankStatement replaces the top-level statement that contains the given line (1-based)
+// with whitespace, keeping newlines so that the positions of other statements don't change.
+// It reports whether anything has been removed.
+func blankStatement(buf []byte, line int) bool {
+	var lineStarts []int
+	for i := 0; i < len(buf); i++ {
+		if i == 0 || buf[i-1] == '\n' {
+			lineStarts = append(lineStarts, i)
+		}
+	}
+	if len(lineStarts) == 0 {
+		return false
+	}
+	// isStatementStart reports whether the i-th line starts a new top-level statement.
+	isStatementStart := func(i int) bool {
+		rest := buf[lineStarts[i]:]
+		if len(rest) == 0 || strings.IndexByte(" \t\r\n#)]}", rest[0]) >= 0 {
+			return false
+		}
+		return !bytes.HasPrefix(rest, []byte("else")) && !bytes.HasPrefix(rest, []byte("elif"))
+	}
+
+	first := line - 1
+	if first >= len(lineStarts) {
+		first = len(lineStarts) - 1
+	}
+	if first < 0 {
+		first = 0
+	}
+	for first > 0 && !isStatementStart(first) {
+		first--
+	}
+	last := first + 1
+	for last < len(lineStarts) && !isStatementStart(last) {
+		last++
+	}
+	end := len(buf)
+	if last < len(lineStarts) {
+		end = lineStarts[last]
+	}
+
+	changed := false
+	for i := lineStarts[first]; i < end; i++ {
+		if buf[i] != '\n' && buf[i] != ' ' {
+			buf[i] = ' '
+			changed = true
+		}
+	}
+	if !changed && end < len(buf) {
+		// The error is in an empty region, remove the next statement instead.
+		return blankStatement(buf, last+1)
+	}
+	return changed
+}
+
 // ParseError contains information about the error encountered during parsing.
 type ParseError struct {
 	Message  string
@@ -159,7 +240,7 @@ func (e ParseError) Error() string {
 	if filename == "" {
 		filename = "<stdin>"
 	}
-	return fmt.Sprintf("%s:%d:%d: %v", filename, e.Pos.Line, e.Pos.LineRune, e.Message)
+	return fmt.Sprintf("%s:%s: %v", filename, e.Pos, e.Message)
 }
 
 // An input represents a single input file being parsed.
@@ -170,6 +251,7 @@ type input struct {
 	remaining      []byte    // remaining input
 	token          []byte    // token being scanned
 	lastToken      string    // most recently returned token, for error messages
+	lastTokenType  int       // type of the most recently returned token, 0 at the start of the file
 	pos            Position  // current input position
 	lineComments   []Comment // accumulated line comments
 	suffixComments []Comment // accumulated suffix comments
@@ -178,6 +260,10 @@ type input struct {
 	indent         int       // current line indentation in spaces
 	indents        []int     // stack of indentation levels in spaces
 
+	// Trailing commas, see File.HasTrailingComma.
+	afterComma     bool              // true if the most recently returned token is a comma
+	trailingCommas map[Position]bool // positions of closing brackets preceded by a comma
+
 	// Parser state.
 	file       *File // returned top-level syntax tree
 	parseError error // error encountered during parsing
@@ -229,6 +315,7 @@ func (in *input) parse() (f *File, err error) {
 		return nil, in.parseError
 	}
 	in.file.Path = in.filename
+	in.file.trailingCommas = in.trailingCommas
 
 	// Assign comments to nearby syntax.
 	in.assignComments()
@@ -310,6 +397,12 @@ func (in *input) endToken(val *yySymType) {
 // val.Pos (the position where the token begins)
 // and val.Token (the input string corresponding to the token).
 func (in *input) Lex(val *yySymType) int {
+	in.lastTokenType = in.lex(val)
+	return in.lastTokenType
+}
+
+// lex scans the next input token, see Lex.
+func (in *input) lex(val *yySymType) int {
 	// Skip past spaces, stopping at non-space or EOF.
 	countNL := 0 // number of newlines we've skipped past
 	for !in.eof() {
@@ -445,6 +538,8 @@ func (in *input) Lex(val *yySymType) int {
 	// Found the beginning of the next token.
 	in.startToken(val)
 	defer in.endToken(val)
+	afterComma := in.afterComma
+	in.afterComma = false
 
 	// End of file.
 	if in.eof() {
@@ -460,11 +555,18 @@ func (in *input) Lex(val *yySymType) int {
 		return c
 
 	case ']', ')', '}':
+		if afterComma {
+			if in.trailingCommas == nil {
+				in.trailingCommas = make(map[Position]bool)
+			}
+			in.trailingCommas[in.pos] = true
+		}
 		in.depth--
 		in.readRune()
 		return c
 
 	case '.', ':', ';', ',': // single-char tokens
+		in.afterComma = c == ','
 		in.readRune()
 		return c
 
@@ -591,6 +693,10 @@ func (in *input) Lex(val *yySymType) int {
 		return _BREAK
 	case "continue":
 		return _CONTINUE
+	case "assert":
+		if in.depth == 0 && in.atStmtStart() && in.startsAssertStmt() {
+			return _ASSERT
+		}
 	}
 	if len(val.tok) > 0 && val.tok[0] >= '0' && val.tok[0] <= '9' {
 		return _NUMBER
@@ -598,6 +704,34 @@ func (in *input) Lex(val *yySymType) int {
 	return _IDENT
 }
 
+// atStmtStart reports whether the token being scanned is the first token of a statement,
+// i.e. it follows the start of the file, a newline, a change of indentation or a semicolon.
+func (in *input) atStmtStart() bool {
+	switch in.lastTokenType {
+	case 0, '\n', ';', _INDENT, _UNINDENT:
+		return true
+	}
+	return false
+}
+
+// startsAssertStmt reports whether the identifier `assert` that has just been scanned
+// at the start of a statement starts an assert statement. Starlark doesn't reserve the
+// word, so `assert` is still an ordinary name if it's followed by anything that can't
+// start the asserted expression: `assert.eq(1, 1)`, `assert(x)`, `assert = ...`,
+// `assert[0]` or `assert == x`.
+func (in *input) startsAssertStmt() bool {
+	rest := bytes.TrimLeft(in.remaining, " \t")
+	if len(rest) == 0 {
+		return false
+	}
+	switch rest[0] {
+	case '(', '.', '=', '[', '!', '<', '>', '*', '/', '%', '|', '&', '^',
+		',', ')', ']', '}', ':', ';', '#', '\r', '\n', '\\':
+		return false
+	}
+	return true
+}
+
 // isIdent reports whether c is an identifier rune.
 // We treat all non-ASCII runes as identifier runes.
 func isIdent(c int) bool {
@@ -746,6 +880,11 @@ func (in *input) order(v Expr) {
 		if v.Result != nil {
 			in.order(v.Result)
 		}
+	case *AssertStmt:
+		in.order(v.Cond)
+		if v.Message != nil {
+			in.order(v.Message)
+		}
 	case *DefStmt:
 		for _, x := range v.Params {
 			in.order(x)
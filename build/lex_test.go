@@ -50,3 +50,30 @@ func TestIsBuildFilename(t *testing.T) {
 		}
 	}
 }
+
+func TestPositionString(t *testing.T) {
+	tests := []struct {
+		path string
+		pos  Position
+		want string
+		file string
+	}{
+		{"BUILD", Position{Line: 1, LineRune: 1}, "1:1", "BUILD:1:1"},
+		{"pkg/foo.bzl", Position{Line: 12, LineRune: 5, Byte: 120}, "12:5", "pkg/foo.bzl:12:5"},
+		{"", Position{Line: 3, LineRune: 10}, "3:10", "<stdin>:3:10"},
+	}
+	for _, tt := range tests {
+		if got := tt.pos.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.pos, got, tt.want)
+		}
+		f := &File{Path: tt.path}
+		if got := f.PosString(tt.pos); got != tt.file {
+			t.Errorf("PosString(%#v) for %q = %q, want %q", tt.pos, tt.path, got, tt.file)
+		}
+	}
+
+	err := ParseError{Message: "syntax error near ,", Filename: "pkg/BUILD", Pos: Position{Line: 2, LineRune: 11}}
+	if got, want := err.Error(), "pkg/BUILD:2:11: syntax error near ,"; got != want {
+		t.Errorf("ParseError.Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// Typed view of MODULE.bazel files.
+
+package build
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// ModuleInfo describes the module declared in a MODULE.bazel file.
+type ModuleInfo struct {
+	Name               string
+	Version            string
+	CompatibilityLevel int
+	BazelDeps          []*BazelDep
+}
+
+// A BazelDep is a single bazel_dep declaration of a MODULE.bazel file.
+type BazelDep struct {
+	Name          string
+	Version       string
+	RepoName      string // empty if not set
+	DevDependency bool
+	Call          *CallExpr
+}
+
+// ModuleInfo returns the module declaration and the dependencies of a MODULE.bazel file,
+// or nil for other files. Attributes that aren't literals are reported as empty values.
+func (f *File) ModuleInfo() *ModuleInfo {
+	if filepath.Base(f.Path) != "MODULE.bazel" {
+		return nil
+	}
+
+	info := &ModuleInfo{}
+	for _, stmt := range f.Stmt {
+		call, ok := stmt.(*CallExpr)
+		if !ok {
+			continue
+		}
+		r := &Rule{call, ""}
+		switch r.Kind() {
+		case "module":
+			info.Name = r.AttrString("name")
+			info.Version = r.AttrString("version")
+			if level, ok := r.Attr("compatibility_level").(*LiteralExpr); ok {
+				info.CompatibilityLevel, _ = strconv.Atoi(level.Token)
+			}
+		case "bazel_dep":
+			info.BazelDeps = append(info.BazelDeps, &BazelDep{
+				Name:          r.AttrString("name"),
+				Version:       r.AttrString("version"),
+				RepoName:      r.AttrString("repo_name"),
+				DevDependency: r.AttrLiteral("dev_dependency") == "True",
+				Call:          call,
+			})
+		}
+	}
+	return info
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package build
+
+import (
+	"testing"
+)
+
+func TestModuleInfo(t *testing.T) {
+	f, err := Parse("foo/MODULE.bazel", []byte(`module(
+    name = "my_module",
+    version = "1.2.3",
+    compatibility_level = 2,
+)
+
+bazel_dep(name = "rules_go", version = "0.39.1")
+bazel_dep(name = "protobuf", version = "21.7", repo_name = "com_google_protobuf")
+bazel_dep(name = "rules_testing", version = "0.1.0", dev_dependency = True)
+
+go_deps = use_extension("@gazelle//:extensions.bzl", "go_deps")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := f.ModuleInfo()
+	if info == nil {
+		t.Fatal("ModuleInfo() = nil for a MODULE.bazel file")
+	}
+	if info.Name != "my_module" || info.Version != "1.2.3" || info.CompatibilityLevel != 2 {
+		t.Errorf("got module %q, version %q, compatibility level %d, want %q, %q, %d",
+			info.Name, info.Version, info.CompatibilityLevel, "my_module", "1.2.3", 2)
+	}
+
+	want := []BazelDep{
+		{Name: "rules_go", Version: "0.39.1"},
+		{Name: "protobuf", Version: "21.7", RepoName: "com_google_protobuf"},
+		{Name: "rules_testing", Version: "0.1.0", DevDependency: true},
+	}
+	if len(info.BazelDeps) != len(want) {
+		t.Fatalf("got %d dependencies, want %d", len(info.BazelDeps), len(want))
+	}
+	for i, dep := range info.BazelDeps {
+		if dep.Call != f.Stmt[i+1] {
+			t.Errorf("dependency #%d: the call doesn't refer to the statement", i)
+		}
+		dep.Call = nil
+		if *dep != want[i] {
+			t.Errorf("dependency #%d: got %+v, want %+v", i, *dep, want[i])
+		}
+	}
+}
+
+func TestModuleInfoNonModuleFile(t *testing.T) {
+	f, err := Parse("BUILD", []byte(`module(name = "foo")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := f.ModuleInfo(); info != nil {
+		t.Errorf("ModuleInfo() = %+v for a BUILD file, want nil", info)
+	}
+}
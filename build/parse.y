@@ -71,6 +71,7 @@ package build
 
 %token	<pos>	_AUGM    // augmented assignment
 %token	<pos>	_AND     // keyword and
+%token	<pos>	_ASSERT  // keyword assert
 %token	<pos>	_COMMENT // top-level # comment
 %token	<pos>	_EOF     // end of file
 %token	<pos>	_EQ      // operator ==
@@ -425,6 +426,21 @@ small_stmt:
 			Return: $1,
 		}
 	}
+|	_ASSERT test
+	{
+		$$ = &AssertStmt{
+			Assert: $1,
+			Cond: $2,
+		}
+	}
+|	_ASSERT test ',' test
+	{
+		$$ = &AssertStmt{
+			Assert: $1,
+			Cond: $2,
+			Message: $4,
+		}
+	}
 |	expr '=' expr      { $$ = binary($1, $2, $<tok>2, $3) }
 |	expr _AUGM expr    { $$ = binary($1, $2, $<tok>2, $3) }
 |	_PASS
@@ -36,35 +36,35 @@ type yySymType struct {
 
 const _AUGM = 57346
 const _AND = 57347
-const _COMMENT = 57348
-const _EOF = 57349
-const _EQ = 57350
-const _FOR = 57351
-const _GE = 57352
-const _IDENT = 57353
-const _NUMBER = 57354
-const _IF = 57355
-const _ELSE = 57356
-const _ELIF = 57357
-const _IN = 57358
-const _IS = 57359
-const _LAMBDA = 57360
-const _LOAD = 57361
-const _LE = 57362
-const _NE = 57363
-const _STAR_STAR = 57364
-const _NOT = 57365
-const _OR = 57366
-const _STRING = 57367
-const _DEF = 57368
-const _RETURN = 57369
-const _PASS = 57370
-const _BREAK = 57371
-const _CONTINUE = 57372
-const _INDENT = 57373
-const _UNINDENT = 57374
-const ShiftInstead = 57375
-const _ASSERT = 57376
+const _ASSERT = 57348
+const _COMMENT = 57349
+const _EOF = 57350
+const _EQ = 57351
+const _FOR = 57352
+const _GE = 57353
+const _IDENT = 57354
+const _NUMBER = 57355
+const _IF = 57356
+const _ELSE = 57357
+const _ELIF = 57358
+const _IN = 57359
+const _IS = 57360
+const _LAMBDA = 57361
+const _LOAD = 57362
+const _LE = 57363
+const _NE = 57364
+const _STAR_STAR = 57365
+const _NOT = 57366
+const _OR = 57367
+const _STRING = 57368
+const _DEF = 57369
+const _RETURN = 57370
+const _PASS = 57371
+const _BREAK = 57372
+const _CONTINUE = 57373
+const _INDENT = 57374
+const _UNINDENT = 57375
+const ShiftInstead = 57376
 const _UNARY = 57377
 
 var yyToknames = [...]string{
@@ -91,6 +91,7 @@ var yyToknames = [...]string{
 	"'|'",
 	"_AUGM",
 	"_AND",
+	"_ASSERT",
 	"_COMMENT",
 	"_EOF",
 	"_EQ",
@@ -120,7 +121,6 @@ var yyToknames = [...]string{
 	"_UNINDENT",
 	"ShiftInstead",
 	"'\\n'",
-	"_ASSERT",
 	"_UNARY",
 	"';'",
 }
@@ -130,7 +130,7 @@ const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-//line build/parse.y:957
+//line build/parse.y:973
 
 // Go helper code.
 
@@ -381,203 +381,210 @@ var yyExca = [...]int{
 
 const yyPrivate = 57344
 
-const yyLast = 693
+const yyLast = 764
 
 var yyAct = [...]int{
 
-	19, 198, 25, 157, 34, 195, 7, 2, 148, 120,
-	129, 133, 80, 39, 132, 88, 9, 155, 208, 219,
-	210, 144, 36, 21, 73, 74, 32, 35, 32, 110,
-	78, 83, 86, 76, 174, 40, 46, 47, 77, 117,
-	35, 38, 135, 207, 94, 150, 209, 63, 91, 98,
-	99, 100, 101, 102, 103, 104, 105, 106, 107, 108,
-	109, 13, 111, 112, 113, 114, 115, 32, 177, 121,
-	85, 122, 91, 202, 31, 135, 44, 79, 151, 178,
-	130, 139, 66, 96, 72, 172, 29, 215, 30, 225,
-	137, 43, 149, 140, 135, 138, 43, 90, 32, 33,
-	167, 97, 41, 92, 93, 145, 27, 95, 82, 42,
-	49, 158, 35, 48, 51, 153, 52, 212, 50, 131,
-	68, 214, 211, 185, 165, 166, 67, 63, 164, 43,
-	162, 125, 69, 188, 160, 43, 43, 176, 203, 154,
-	116, 168, 179, 181, 173, 127, 184, 171, 163, 175,
-	173, 43, 152, 186, 187, 180, 142, 136, 183, 40,
-	192, 128, 221, 182, 121, 194, 122, 161, 143, 196,
-	89, 75, 189, 193, 200, 201, 199, 49, 87, 191,
-	48, 1, 197, 190, 28, 50, 205, 149, 84, 169,
-	170, 81, 37, 204, 63, 45, 18, 12, 8, 4,
-	31, 26, 134, 216, 70, 24, 206, 71, 213, 126,
-	146, 147, 29, 196, 30, 218, 222, 200, 220, 199,
-	223, 217, 7, 31, 32, 33, 118, 119, 24, 0,
-	0, 22, 27, 0, 0, 29, 23, 30, 35, 0,
-	0, 0, 6, 0, 0, 11, 0, 32, 33, 20,
-	0, 0, 0, 0, 22, 27, 0, 0, 0, 23,
-	0, 35, 10, 14, 15, 16, 17, 31, 224, 0,
-	5, 0, 24, 0, 0, 0, 0, 0, 0, 29,
-	0, 30, 0, 0, 0, 0, 6, 3, 0, 11,
-	0, 32, 33, 20, 0, 0, 0, 0, 22, 27,
-	0, 0, 0, 23, 0, 35, 10, 14, 15, 16,
-	17, 31, 0, 0, 5, 0, 24, 0, 0, 0,
-	0, 0, 0, 29, 0, 30, 0, 0, 0, 0,
-	0, 0, 31, 0, 0, 32, 33, 24, 0, 0,
-	0, 0, 22, 27, 29, 0, 30, 23, 0, 35,
-	0, 14, 15, 16, 17, 0, 32, 33, 156, 0,
-	0, 0, 0, 22, 27, 0, 0, 0, 23, 0,
-	35, 0, 14, 15, 16, 17, 49, 0, 0, 48,
-	51, 0, 52, 0, 50, 141, 53, 0, 54, 0,
-	0, 0, 0, 63, 0, 62, 0, 0, 55, 0,
-	58, 0, 0, 65, 0, 0, 59, 64, 0, 0,
-	56, 57, 49, 60, 61, 48, 51, 0, 52, 0,
-	50, 0, 53, 0, 54, 0, 0, 0, 0, 63,
-	0, 62, 0, 0, 55, 0, 58, 0, 0, 65,
-	159, 0, 59, 64, 0, 0, 56, 57, 49, 60,
-	61, 48, 51, 0, 52, 0, 50, 0, 53, 0,
-	54, 0, 0, 0, 0, 63, 0, 62, 0, 0,
-	55, 135, 58, 0, 0, 65, 0, 0, 59, 64,
-	0, 0, 56, 57, 49, 60, 61, 48, 51, 0,
-	52, 0, 50, 0, 53, 0, 54, 0, 0, 0,
-	0, 63, 0, 62, 0, 0, 55, 0, 58, 0,
-	0, 65, 0, 0, 59, 64, 0, 0, 56, 57,
-	49, 60, 61, 48, 51, 0, 52, 0, 50, 0,
-	53, 0, 54, 0, 0, 0, 0, 63, 0, 62,
-	0, 0, 55, 0, 58, 0, 0, 0, 0, 0,
-	59, 64, 0, 0, 56, 57, 49, 60, 61, 48,
-	51, 0, 52, 0, 50, 0, 53, 0, 54, 0,
-	0, 0, 0, 63, 0, 62, 0, 0, 55, 0,
-	58, 0, 0, 0, 0, 0, 59, 0, 0, 0,
-	56, 57, 49, 60, 61, 48, 51, 0, 52, 0,
-	50, 0, 53, 0, 54, 0, 0, 0, 0, 63,
-	0, 62, 0, 0, 55, 0, 58, 0, 31, 0,
-	123, 0, 59, 24, 0, 0, 56, 57, 0, 60,
-	29, 0, 30, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 32, 33, 0, 0, 0, 0, 0, 22,
-	27, 0, 0, 124, 23, 49, 35, 0, 48, 51,
-	0, 52, 0, 50, 0, 53, 0, 54, 0, 0,
-	0, 0, 63, 0, 0, 0, 0, 55, 0, 58,
-	0, 0, 0, 0, 0, 59, 0, 0, 0, 56,
-	57, 0, 60,
+	20, 202, 26, 161, 35, 199, 7, 2, 151, 123,
+	132, 136, 82, 40, 9, 22, 46, 159, 135, 212,
+	90, 223, 214, 147, 37, 75, 76, 41, 33, 36,
+	113, 80, 85, 88, 78, 48, 49, 79, 178, 33,
+	120, 39, 36, 138, 211, 96, 65, 213, 93, 153,
+	93, 101, 102, 103, 104, 105, 106, 107, 108, 109,
+	110, 111, 112, 32, 114, 115, 116, 117, 118, 13,
+	87, 124, 33, 125, 206, 30, 92, 31, 99, 81,
+	176, 182, 133, 154, 45, 229, 142, 134, 33, 34,
+	138, 68, 140, 74, 152, 143, 28, 100, 157, 181,
+	219, 141, 36, 216, 70, 148, 189, 138, 215, 156,
+	69, 84, 94, 95, 162, 51, 71, 98, 50, 53,
+	168, 54, 51, 52, 218, 50, 207, 169, 170, 44,
+	52, 188, 65, 166, 44, 42, 172, 164, 175, 65,
+	180, 128, 43, 171, 44, 183, 185, 177, 192, 130,
+	167, 44, 179, 177, 41, 158, 190, 191, 184, 44,
+	44, 187, 155, 119, 196, 145, 139, 131, 124, 198,
+	125, 225, 186, 200, 165, 146, 193, 197, 204, 205,
+	203, 91, 77, 89, 195, 1, 201, 194, 29, 86,
+	209, 152, 83, 38, 47, 19, 12, 208, 8, 4,
+	173, 174, 27, 137, 72, 73, 129, 220, 149, 150,
+	210, 121, 217, 122, 0, 0, 0, 200, 0, 222,
+	226, 204, 224, 203, 227, 221, 7, 32, 0, 0,
+	0, 0, 25, 0, 0, 0, 0, 0, 0, 30,
+	0, 31, 0, 0, 0, 0, 15, 6, 0, 0,
+	11, 0, 33, 34, 21, 0, 0, 0, 0, 23,
+	28, 0, 0, 0, 24, 0, 36, 10, 14, 16,
+	17, 18, 32, 228, 0, 5, 0, 25, 0, 0,
+	0, 0, 0, 0, 30, 0, 31, 0, 0, 0,
+	0, 15, 6, 3, 0, 11, 0, 33, 34, 21,
+	0, 0, 0, 0, 23, 28, 0, 0, 0, 24,
+	0, 36, 10, 14, 16, 17, 18, 32, 0, 0,
+	5, 0, 25, 0, 0, 0, 0, 0, 0, 30,
+	0, 31, 0, 0, 0, 0, 15, 0, 0, 0,
+	0, 0, 33, 34, 0, 0, 0, 0, 0, 23,
+	28, 0, 32, 0, 24, 0, 36, 25, 14, 16,
+	17, 18, 0, 0, 30, 160, 31, 0, 0, 0,
+	0, 15, 0, 0, 0, 0, 0, 33, 34, 0,
+	0, 0, 0, 0, 23, 28, 0, 0, 0, 24,
+	0, 36, 0, 14, 16, 17, 18, 51, 0, 0,
+	50, 53, 0, 54, 0, 52, 144, 55, 0, 56,
+	0, 0, 0, 0, 65, 0, 64, 0, 0, 0,
+	57, 0, 60, 0, 0, 67, 0, 0, 61, 66,
+	0, 0, 58, 59, 51, 62, 63, 50, 53, 0,
+	54, 0, 52, 0, 55, 0, 56, 0, 0, 0,
+	0, 65, 0, 64, 0, 0, 0, 57, 0, 60,
+	0, 0, 67, 163, 0, 61, 66, 0, 0, 58,
+	59, 51, 62, 63, 50, 53, 0, 54, 0, 52,
+	0, 55, 0, 56, 0, 0, 0, 0, 65, 0,
+	64, 0, 0, 0, 57, 138, 60, 0, 0, 67,
+	0, 0, 61, 66, 0, 0, 58, 59, 51, 62,
+	63, 50, 53, 97, 54, 0, 52, 0, 55, 0,
+	56, 0, 0, 0, 0, 65, 0, 64, 0, 0,
+	0, 57, 0, 60, 0, 0, 67, 0, 0, 61,
+	66, 0, 0, 58, 59, 51, 62, 63, 50, 53,
+	0, 54, 0, 52, 0, 55, 0, 56, 0, 0,
+	0, 0, 65, 0, 64, 0, 0, 0, 57, 0,
+	60, 0, 0, 67, 0, 0, 61, 66, 0, 0,
+	58, 59, 51, 62, 63, 50, 53, 0, 54, 0,
+	52, 0, 55, 0, 56, 0, 0, 0, 0, 65,
+	0, 64, 0, 0, 0, 57, 0, 60, 0, 0,
+	0, 0, 0, 61, 66, 0, 0, 58, 59, 51,
+	62, 63, 50, 53, 0, 54, 0, 52, 0, 55,
+	0, 56, 0, 0, 0, 0, 65, 0, 64, 0,
+	0, 0, 57, 0, 60, 0, 0, 0, 0, 0,
+	61, 0, 0, 0, 58, 59, 51, 62, 63, 50,
+	53, 0, 54, 0, 52, 0, 55, 0, 56, 0,
+	0, 0, 32, 65, 126, 64, 0, 25, 0, 57,
+	0, 60, 0, 0, 30, 0, 31, 61, 0, 0,
+	0, 58, 59, 0, 62, 0, 0, 33, 34, 0,
+	0, 0, 0, 0, 23, 28, 0, 0, 127, 24,
+	51, 36, 0, 50, 53, 0, 54, 0, 52, 0,
+	55, 0, 56, 0, 32, 0, 0, 65, 0, 25,
+	0, 0, 0, 57, 0, 60, 30, 0, 31, 0,
+	0, 61, 0, 0, 0, 58, 59, 0, 62, 33,
+	34, 0, 0, 0, 0, 0, 23, 28, 0, 0,
+	0, 24, 0, 36,
 }
 var yyPact = [...]int{
 
-	-1000, -1000, 262, -1000, -1000, -1000, -30, -1000, -1000, -1000,
-	12, 69, -1000, 87, 195, -1000, -1000, -1000, 4, 480,
-	195, 115, 195, 195, 195, -1000, -1000, 166, -16, 195,
-	195, 195, -1000, -1000, -1000, -1000, -1000, -40, 165, 63,
-	115, 195, 195, 195, 142, 195, 70, -1000, 195, 195,
-	195, 195, 195, 195, 195, 195, 195, 195, 195, 195,
-	-5, 195, 195, 195, 195, 195, 127, 10, 613, 195,
-	132, 152, 142, -1000, -1000, -16, -1000, 101, 444, 148,
-	15, 61, 148, 372, 147, 162, 480, -31, 327, 38,
-	195, 69, 142, 142, 516, 126, 306, -1000, 26, 26,
-	26, 173, 173, 106, 106, 106, 106, 106, 106, 106,
-	195, 588, 651, -1000, 552, 408, 306, -1000, 161, 139,
-	-1000, 480, 113, 195, 195, 82, 128, 195, 195, -1000,
-	138, -1000, 67, 3, -1000, 69, 195, -1000, 48, -1000,
-	59, 195, 195, -1000, -1000, -1000, 157, 137, -1000, 108,
-	-1, -1, 120, 115, 306, -1000, -1000, -1000, 106, 195,
-	-1000, -1000, -1000, 613, 195, 480, 480, -1000, 195, -1000,
-	-1000, -3, -1000, 3, 195, 39, 480, -1000, -1000, 480,
-	-1000, 372, 125, -1000, 38, 195, -1000, -1000, 306, -1000,
-	-6, -32, 516, -1000, 480, 104, 480, 112, -1000, -1000,
-	72, 516, 195, 306, -1000, 480, -1000, -1000, -33, -1000,
-	-1000, -1000, 195, 156, -3, -16, 516, -1000, 218, -1000,
-	71, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, 267, -1000, -1000, -1000, -29, -1000, -1000, -1000,
+	11, 58, -1000, 120, 719, 719, -1000, -1000, -1000, 2,
+	541, 719, 99, 719, 719, 719, -1000, -1000, 177, -15,
+	719, 719, 719, -1000, -1000, -1000, -1000, -1000, -35, 176,
+	41, 99, 719, 719, 719, 151, 504, 719, 65, -1000,
+	719, 719, 719, 719, 719, 719, 719, 719, 719, 719,
+	719, 719, -5, 719, 719, 719, 719, 719, 150, 10,
+	667, 719, 136, 158, 151, -1000, -1000, -15, -1000, 69,
+	467, 157, 15, 66, 157, 393, 156, 169, 541, -30,
+	347, 42, 719, 58, 151, 151, 578, 719, 142, 312,
+	-1000, 25, 25, 25, 118, 118, 111, 111, 111, 111,
+	111, 111, 111, 719, 652, 706, -1000, 615, 430, 312,
+	-1000, 168, 141, -1000, 541, 105, 719, 719, 125, 123,
+	719, 719, -1000, 129, -1000, 62, 6, -1000, 58, 719,
+	-1000, 79, -1000, 61, 719, 719, -1000, -1000, -1000, 166,
+	122, -1000, 91, 9, 9, 135, 99, 541, 312, -1000,
+	-1000, -1000, 111, 719, -1000, -1000, -1000, 667, 719, 541,
+	541, -1000, 719, -1000, -1000, -2, -1000, 6, 719, 39,
+	541, -1000, -1000, 541, -1000, 393, 113, -1000, 42, 719,
+	-1000, -1000, 312, -1000, -6, -31, 578, -1000, 541, 90,
+	541, 115, -1000, -1000, 85, 578, 719, 312, -1000, 541,
+	-1000, -1000, -32, -1000, -1000, -1000, 719, 165, -2, -15,
+	578, -1000, 222, -1000, 67, -1000, -1000, -1000, -1000, -1000,
 }
 var yyPgo = [...]int{
 
-	0, 10, 9, 227, 226, 8, 211, 210, 0, 5,
-	38, 23, 61, 209, 77, 207, 204, 13, 202, 11,
-	14, 2, 201, 7, 199, 198, 197, 196, 195, 3,
-	16, 192, 12, 191, 188, 4, 184, 17, 183, 1,
-	182, 181, 179, 178,
+	0, 10, 9, 213, 211, 8, 209, 208, 0, 5,
+	37, 15, 69, 206, 79, 205, 204, 13, 203, 11,
+	18, 2, 202, 7, 199, 198, 196, 195, 194, 3,
+	14, 193, 12, 192, 189, 4, 188, 17, 187, 1,
+	186, 185, 184, 183,
 }
 var yyR1 = [...]int{
 
 	0, 41, 37, 37, 42, 42, 38, 38, 38, 23,
 	23, 23, 23, 24, 24, 25, 25, 25, 27, 27,
 	26, 26, 28, 28, 29, 31, 31, 30, 30, 30,
-	30, 30, 30, 30, 30, 43, 43, 11, 11, 11,
+	30, 30, 30, 30, 30, 30, 30, 43, 43, 11,
 	11, 11, 11, 11, 11, 11, 11, 11, 11, 11,
-	11, 11, 4, 4, 3, 3, 2, 2, 2, 2,
-	40, 40, 39, 39, 7, 7, 6, 6, 5, 5,
-	5, 5, 12, 12, 13, 13, 15, 15, 16, 16,
+	11, 11, 11, 11, 4, 4, 3, 3, 2, 2,
+	2, 2, 40, 40, 39, 39, 7, 7, 6, 6,
+	5, 5, 5, 5, 12, 12, 13, 13, 15, 15,
+	16, 16, 8, 8, 8, 8, 8, 8, 8, 8,
 	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	8, 8, 14, 14, 9, 9, 10, 10, 1, 1,
-	32, 34, 34, 33, 33, 33, 17, 17, 35, 36,
-	36, 21, 22, 18, 19, 19, 20, 20,
+	8, 8, 8, 8, 14, 14, 9, 9, 10, 10,
+	1, 1, 32, 34, 34, 33, 33, 33, 17, 17,
+	35, 36, 36, 21, 22, 18, 19, 19, 20, 20,
 }
 var yyR2 = [...]int{
 
 	0, 2, 5, 2, 0, 2, 0, 3, 2, 0,
 	2, 2, 3, 1, 1, 7, 6, 1, 4, 5,
 	1, 4, 2, 1, 4, 0, 3, 1, 2, 1,
-	3, 3, 1, 1, 1, 0, 1, 1, 1, 3,
-	7, 4, 4, 6, 8, 1, 3, 4, 4, 3,
-	4, 3, 0, 2, 1, 3, 1, 3, 2, 2,
-	1, 3, 1, 3, 0, 2, 1, 3, 1, 3,
-	2, 2, 1, 3, 0, 1, 1, 3, 0, 2,
-	1, 4, 2, 2, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 4, 3, 3, 3,
-	3, 5, 1, 3, 0, 1, 0, 2, 0, 1,
-	3, 1, 3, 0, 1, 2, 1, 3, 1, 1,
-	2, 1, 1, 4, 1, 3, 1, 2,
+	2, 4, 3, 3, 1, 1, 1, 0, 1, 1,
+	1, 3, 7, 4, 4, 6, 8, 1, 3, 4,
+	4, 3, 4, 3, 0, 2, 1, 3, 1, 3,
+	2, 2, 1, 3, 1, 3, 0, 2, 1, 3,
+	1, 3, 2, 2, 1, 3, 0, 1, 1, 3,
+	0, 2, 1, 4, 2, 2, 3, 3, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 4, 3,
+	3, 3, 3, 5, 1, 3, 0, 1, 0, 2,
+	0, 1, 3, 1, 3, 0, 1, 2, 1, 3,
+	1, 1, 2, 1, 1, 4, 1, 3, 1, 2,
 }
 var yyChk = [...]int{
 
-	-1000, -41, -23, 25, -24, 52, 24, -29, -25, -30,
-	44, 27, -26, -12, 45, 46, 47, 48, -27, -8,
-	31, -11, 36, 41, 10, -21, -22, 37, -36, 17,
-	19, 5, 29, 30, -35, 43, 52, -31, 29, -17,
-	-11, 15, 22, 9, -12, -28, 32, 33, 7, 4,
-	12, 8, 10, 14, 16, 26, 38, 39, 28, 34,
-	41, 42, 23, 21, 35, 31, -12, 11, 5, 17,
-	-16, -15, -12, -8, -8, 5, -35, -10, -8, -14,
-	-32, -33, -14, -8, -34, -10, -8, -43, 55, 5,
-	34, 9, -12, -12, -8, -12, 13, 31, -8, -8,
-	-8, -8, -8, -8, -8, -8, -8, -8, -8, -8,
-	34, -8, -8, -8, -8, -8, 13, 29, -4, -3,
-	-2, -8, -21, 7, 40, -12, -13, 13, 9, -1,
-	-35, 18, -20, -19, -18, 27, 9, -1, -20, 20,
-	-1, 13, 9, 6, 52, -30, -7, -6, -5, -21,
-	7, 40, -12, -11, 13, -37, 52, -29, -8, 32,
-	-37, 6, -1, 9, 15, -8, -8, 18, 13, -12,
-	-12, 9, 18, -19, 31, -17, -8, 20, 20, -8,
-	-32, -8, 6, -1, 9, 15, -21, -21, 13, -37,
-	-38, -42, -8, -2, -8, -9, -8, -40, -39, -35,
-	-21, -8, 34, 13, -5, -8, -37, 49, 24, 52,
-	52, 18, 13, -1, 9, 15, -8, -37, -23, 52,
-	-9, 6, -39, -35, 50, 18,
+	-1000, -41, -23, 26, -24, 53, 25, -29, -25, -30,
+	45, 28, -26, -12, 46, 24, 47, 48, 49, -27,
+	-8, 32, -11, 37, 42, 10, -21, -22, 38, -36,
+	17, 19, 5, 30, 31, -35, 44, 53, -31, 30,
+	-17, -11, 15, 22, 9, -12, -8, -28, 33, 34,
+	7, 4, 12, 8, 10, 14, 16, 27, 39, 40,
+	29, 35, 42, 43, 23, 21, 36, 32, -12, 11,
+	5, 17, -16, -15, -12, -8, -8, 5, -35, -10,
+	-8, -14, -32, -33, -14, -8, -34, -10, -8, -43,
+	55, 5, 35, 9, -12, -12, -8, 9, -12, 13,
+	32, -8, -8, -8, -8, -8, -8, -8, -8, -8,
+	-8, -8, -8, 35, -8, -8, -8, -8, -8, 13,
+	30, -4, -3, -2, -8, -21, 7, 41, -12, -13,
+	13, 9, -1, -35, 18, -20, -19, -18, 28, 9,
+	-1, -20, 20, -1, 13, 9, 6, 53, -30, -7,
+	-6, -5, -21, 7, 41, -12, -11, -8, 13, -37,
+	53, -29, -8, 33, -37, 6, -1, 9, 15, -8,
+	-8, 18, 13, -12, -12, 9, 18, -19, 32, -17,
+	-8, 20, 20, -8, -32, -8, 6, -1, 9, 15,
+	-21, -21, 13, -37, -38, -42, -8, -2, -8, -9,
+	-8, -40, -39, -35, -21, -8, 35, 13, -5, -8,
+	-37, 50, 25, 53, 53, 18, 13, -1, 9, 15,
+	-8, -37, -23, 53, -9, 6, -39, -35, 51, 18,
 }
 var yyDef = [...]int{
 
 	9, -2, 0, 1, 10, 11, 0, 13, 14, 25,
-	0, 0, 17, 27, 29, 32, 33, 34, 20, 72,
-	0, 80, 78, 0, 0, 37, 38, 0, 45, 106,
-	113, 106, 121, 122, 119, 118, 12, 35, 0, 0,
-	116, 0, 0, 0, 28, 0, 0, 23, 0, 0,
+	0, 0, 17, 27, 29, 0, 34, 35, 36, 20,
+	74, 0, 82, 80, 0, 0, 39, 40, 0, 47,
+	108, 115, 108, 123, 124, 121, 120, 12, 37, 0,
+	0, 118, 0, 0, 0, 28, 30, 0, 0, 23,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 52, 74,
-	0, 108, 76, 82, 83, 0, 120, 0, 102, 108,
-	111, 0, 108, 102, 114, 0, 102, 0, 36, 64,
-	0, 0, 30, 31, 73, 0, 0, 22, 84, 85,
-	86, 87, 88, 89, 90, 91, 92, 93, 94, 95,
-	0, 97, 98, 99, 100, 0, 0, 39, 0, 108,
-	54, 56, 37, 0, 0, 75, 0, 0, 109, 79,
-	0, 46, 0, 126, 124, 0, 109, 107, 0, 49,
-	0, 0, 115, 51, 24, 26, 0, 108, 66, 68,
-	0, 0, 0, 117, 0, 21, 6, 4, 96, 0,
-	18, 41, 53, 109, 0, 58, 59, 42, 104, 81,
-	77, 0, 47, 127, 0, 0, 103, 48, 50, 110,
-	112, 0, 0, 65, 109, 0, 70, 71, 0, 19,
-	0, 3, 101, 55, 57, 0, 105, 108, 60, 62,
-	0, 125, 0, 0, 67, 69, 16, 9, 0, 8,
-	5, 43, 104, 0, 109, 0, 123, 15, 0, 7,
-	0, 40, 61, 63, 2, 44,
+	54, 76, 0, 110, 78, 84, 85, 0, 122, 0,
+	104, 110, 113, 0, 110, 104, 116, 0, 104, 0,
+	38, 66, 0, 0, 32, 33, 75, 0, 0, 0,
+	22, 86, 87, 88, 89, 90, 91, 92, 93, 94,
+	95, 96, 97, 0, 99, 100, 101, 102, 0, 0,
+	41, 0, 110, 56, 58, 39, 0, 0, 77, 0,
+	0, 111, 81, 0, 48, 0, 128, 126, 0, 111,
+	109, 0, 51, 0, 0, 117, 53, 24, 26, 0,
+	110, 68, 70, 0, 0, 0, 119, 31, 0, 21,
+	6, 4, 98, 0, 18, 43, 55, 111, 0, 60,
+	61, 44, 106, 83, 79, 0, 49, 129, 0, 0,
+	105, 50, 52, 112, 114, 0, 0, 67, 111, 0,
+	72, 73, 0, 19, 0, 3, 103, 57, 59, 0,
+	107, 110, 62, 64, 0, 127, 0, 0, 69, 71,
+	16, 9, 0, 8, 5, 45, 106, 0, 111, 0,
+	125, 15, 0, 7, 0, 42, 63, 65, 2, 46,
 }
 var yyTok1 = [...]int{
 
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	52, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	53, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 4, 3, 3,
 	5, 6, 7, 8, 9, 10, 11, 12, 3, 3,
@@ -595,7 +602,7 @@ var yyTok2 = [...]int{
 	2, 3, 22, 23, 24, 25, 26, 27, 28, 29,
 	30, 31, 32, 33, 34, 35, 36, 37, 38, 39,
 	40, 41, 42, 43, 44, 45, 46, 47, 48, 49,
-	50, 51, 53, 54,
+	50, 51, 52, 54,
 }
 var yyTok3 = [...]int{
 	0,
@@ -940,14 +947,14 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:186
+//line build/parse.y:187
 		{
 			yylex.(*input).file = &File{Stmt: yyDollar[1].exprs}
 			return 0
 		}
 	case 2:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line build/parse.y:193
+//line build/parse.y:194
 		{
 			statements := yyDollar[4].exprs
 			if yyDollar[2].exprs != nil {
@@ -969,20 +976,20 @@ yydefault:
 		}
 	case 3:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:213
+//line build/parse.y:214
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 		}
 	case 6:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:221
+//line build/parse.y:222
 		{
 			yyVAL.exprs = nil
 			yyVAL.lastStmt = nil
 		}
 	case 7:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:226
+//line build/parse.y:227
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 			yyVAL.lastStmt = yyDollar[1].lastStmt
@@ -996,21 +1003,21 @@ yydefault:
 		}
 	case 8:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:238
+//line build/parse.y:239
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 			yyVAL.lastStmt = nil
 		}
 	case 9:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:244
+//line build/parse.y:245
 		{
 			yyVAL.exprs = nil
 			yyVAL.lastStmt = nil
 		}
 	case 10:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:249
+//line build/parse.y:250
 		{
 			// If this statement follows a comment block,
 			// attach the comments to the statement.
@@ -1043,7 +1050,7 @@ yydefault:
 		}
 	case 11:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:280
+//line build/parse.y:281
 		{
 			// Blank line; sever last rule from future comments.
 			yyVAL.exprs = yyDollar[1].exprs
@@ -1051,7 +1058,7 @@ yydefault:
 		}
 	case 12:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:286
+//line build/parse.y:287
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 			yyVAL.lastStmt = yyDollar[1].lastStmt
@@ -1065,14 +1072,14 @@ yydefault:
 		}
 	case 13:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:300
+//line build/parse.y:301
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 			yyVAL.lastStmt = yyDollar[1].exprs[len(yyDollar[1].exprs)-1]
 		}
 	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:305
+//line build/parse.y:306
 		{
 			yyVAL.exprs = []Expr{yyDollar[1].expr}
 			yyVAL.lastStmt = yyDollar[1].expr
@@ -1086,7 +1093,7 @@ yydefault:
 		}
 	case 15:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line build/parse.y:319
+//line build/parse.y:320
 		{
 			yyVAL.expr = &DefStmt{
 				Function: Function{
@@ -1103,7 +1110,7 @@ yydefault:
 		}
 	case 16:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line build/parse.y:334
+//line build/parse.y:335
 		{
 			yyVAL.expr = &ForStmt{
 				For:  yyDollar[1].pos,
@@ -1115,14 +1122,14 @@ yydefault:
 		}
 	case 17:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:344
+//line build/parse.y:345
 		{
 			yyVAL.expr = yyDollar[1].ifstmt
 			yyVAL.lastStmt = yyDollar[1].lastStmt
 		}
 	case 18:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:352
+//line build/parse.y:353
 		{
 			yyVAL.ifstmt = &IfStmt{
 				If:   yyDollar[1].pos,
@@ -1133,7 +1140,7 @@ yydefault:
 		}
 	case 19:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line build/parse.y:361
+//line build/parse.y:362
 		{
 			yyVAL.ifstmt = yyDollar[1].ifstmt
 			inner := yyDollar[1].ifstmt
@@ -1152,7 +1159,7 @@ yydefault:
 		}
 	case 21:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:382
+//line build/parse.y:383
 		{
 			yyVAL.ifstmt = yyDollar[1].ifstmt
 			inner := yyDollar[1].ifstmt
@@ -1165,26 +1172,26 @@ yydefault:
 		}
 	case 24:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:399
+//line build/parse.y:400
 		{
 			yyVAL.exprs = append([]Expr{yyDollar[1].expr}, yyDollar[2].exprs...)
 			yyVAL.lastStmt = yyVAL.exprs[len(yyVAL.exprs)-1]
 		}
 	case 25:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:405
+//line build/parse.y:406
 		{
 			yyVAL.exprs = []Expr{}
 		}
 	case 26:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:409
+//line build/parse.y:410
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
 		}
 	case 28:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:416
+//line build/parse.y:417
 		{
 			yyVAL.expr = &ReturnStmt{
 				Return: yyDollar[1].pos,
@@ -1193,54 +1200,73 @@ yydefault:
 		}
 	case 29:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:423
+//line build/parse.y:424
 		{
 			yyVAL.expr = &ReturnStmt{
 				Return: yyDollar[1].pos,
 			}
 		}
 	case 30:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line build/parse.y:430
+		{
+			yyVAL.expr = &AssertStmt{
+				Assert: yyDollar[1].pos,
+				Cond:   yyDollar[2].expr,
+			}
+		}
+	case 31:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line build/parse.y:437
+		{
+			yyVAL.expr = &AssertStmt{
+				Assert:  yyDollar[1].pos,
+				Cond:    yyDollar[2].expr,
+				Message: yyDollar[4].expr,
+			}
+		}
+	case 32:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:428
+//line build/parse.y:444
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
-	case 31:
+	case 33:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:429
+//line build/parse.y:445
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
-	case 32:
+	case 34:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:431
+//line build/parse.y:447
 		{
 			yyVAL.expr = &BranchStmt{
 				Token:    yyDollar[1].tok,
 				TokenPos: yyDollar[1].pos,
 			}
 		}
-	case 33:
+	case 35:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:438
+//line build/parse.y:454
 		{
 			yyVAL.expr = &BranchStmt{
 				Token:    yyDollar[1].tok,
 				TokenPos: yyDollar[1].pos,
 			}
 		}
-	case 34:
+	case 36:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:445
+//line build/parse.y:461
 		{
 			yyVAL.expr = &BranchStmt{
 				Token:    yyDollar[1].tok,
 				TokenPos: yyDollar[1].pos,
 			}
 		}
-	case 39:
+	case 41:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:459
+//line build/parse.y:475
 		{
 			yyVAL.expr = &DotExpr{
 				X:       yyDollar[1].expr,
@@ -1249,9 +1275,9 @@ yydefault:
 				Name:    yyDollar[3].tok,
 			}
 		}
-	case 40:
+	case 42:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line build/parse.y:468
+//line build/parse.y:484
 		{
 			load := &LoadStmt{
 				Load:         yyDollar[1].pos,
@@ -1265,9 +1291,9 @@ yydefault:
 			}
 			yyVAL.expr = load
 		}
-	case 41:
+	case 43:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:482
+//line build/parse.y:498
 		{
 			yyVAL.expr = &CallExpr{
 				X:              yyDollar[1].expr,
@@ -1278,9 +1304,9 @@ yydefault:
 				ForceMultiLine: forceMultiLine(yyDollar[2].pos, yyDollar[3].exprs, yyDollar[4].pos),
 			}
 		}
-	case 42:
+	case 44:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:493
+//line build/parse.y:509
 		{
 			yyVAL.expr = &IndexExpr{
 				X:          yyDollar[1].expr,
@@ -1289,9 +1315,9 @@ yydefault:
 				End:        yyDollar[4].pos,
 			}
 		}
-	case 43:
+	case 45:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line build/parse.y:502
+//line build/parse.y:518
 		{
 			yyVAL.expr = &SliceExpr{
 				X:          yyDollar[1].expr,
@@ -1302,9 +1328,9 @@ yydefault:
 				End:        yyDollar[6].pos,
 			}
 		}
-	case 44:
+	case 46:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line build/parse.y:513
+//line build/parse.y:529
 		{
 			yyVAL.expr = &SliceExpr{
 				X:           yyDollar[1].expr,
@@ -1317,9 +1343,9 @@ yydefault:
 				End:         yyDollar[8].pos,
 			}
 		}
-	case 45:
+	case 47:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:526
+//line build/parse.y:542
 		{
 			if len(yyDollar[1].strings) == 1 {
 				yyVAL.expr = yyDollar[1].strings[0]
@@ -1331,9 +1357,9 @@ yydefault:
 				yyVAL.expr = binary(yyVAL.expr, end, "+", x)
 			}
 		}
-	case 46:
+	case 48:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:538
+//line build/parse.y:554
 		{
 			yyVAL.expr = &ListExpr{
 				Start:          yyDollar[1].pos,
@@ -1342,9 +1368,9 @@ yydefault:
 				ForceMultiLine: forceMultiLine(yyDollar[1].pos, yyDollar[2].exprs, yyDollar[3].pos),
 			}
 		}
-	case 47:
+	case 49:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:547
+//line build/parse.y:563
 		{
 			yyVAL.expr = &Comprehension{
 				Curly:          false,
@@ -1355,9 +1381,9 @@ yydefault:
 				ForceMultiLine: forceMultiLineComprehension(yyDollar[1].pos, yyDollar[2].expr, yyDollar[3].exprs, yyDollar[4].pos),
 			}
 		}
-	case 48:
+	case 50:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:558
+//line build/parse.y:574
 		{
 			yyVAL.expr = &Comprehension{
 				Curly:          true,
@@ -1368,9 +1394,9 @@ yydefault:
 				ForceMultiLine: forceMultiLineComprehension(yyDollar[1].pos, yyDollar[2].expr, yyDollar[3].exprs, yyDollar[4].pos),
 			}
 		}
-	case 49:
+	case 51:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:569
+//line build/parse.y:585
 		{
 			yyVAL.expr = &DictExpr{
 				Start:          yyDollar[1].pos,
@@ -1379,9 +1405,9 @@ yydefault:
 				ForceMultiLine: forceMultiLine(yyDollar[1].pos, yyDollar[2].exprs, yyDollar[3].pos),
 			}
 		}
-	case 50:
+	case 52:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:578
+//line build/parse.y:594
 		{
 			yyVAL.expr = &SetExpr{
 				Start:          yyDollar[1].pos,
@@ -1390,9 +1416,9 @@ yydefault:
 				ForceMultiLine: forceMultiLine(yyDollar[1].pos, yyDollar[2].exprs, yyDollar[4].pos),
 			}
 		}
-	case 51:
+	case 53:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:587
+//line build/parse.y:603
 		{
 			if len(yyDollar[2].exprs) == 1 && yyDollar[2].comma.Line == 0 {
 				// Just a parenthesized expression, not a tuple.
@@ -1412,67 +1438,67 @@ yydefault:
 				}
 			}
 		}
-	case 52:
+	case 54:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:608
+//line build/parse.y:624
 		{
 			yyVAL.exprs = nil
 		}
-	case 53:
+	case 55:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:612
+//line build/parse.y:628
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 		}
-	case 54:
+	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:618
+//line build/parse.y:634
 		{
 			yyVAL.exprs = []Expr{yyDollar[1].expr}
 		}
-	case 55:
+	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:622
+//line build/parse.y:638
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
 		}
-	case 57:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:629
+//line build/parse.y:645
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
-	case 58:
+	case 60:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:633
+//line build/parse.y:649
 		{
 			yyVAL.expr = unary(yyDollar[1].pos, yyDollar[1].tok, yyDollar[2].expr)
 		}
-	case 59:
+	case 61:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:637
+//line build/parse.y:653
 		{
 			yyVAL.expr = unary(yyDollar[1].pos, yyDollar[1].tok, yyDollar[2].expr)
 		}
-	case 60:
+	case 62:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:642
+//line build/parse.y:658
 		{
 			yyVAL.loadargs = []*struct {
 				from Ident
 				to   Ident
 			}{yyDollar[1].loadarg}
 		}
-	case 61:
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:646
+//line build/parse.y:662
 		{
 			yyDollar[1].loadargs = append(yyDollar[1].loadargs, yyDollar[3].loadarg)
 			yyVAL.loadargs = yyDollar[1].loadargs
 		}
-	case 62:
+	case 64:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:652
+//line build/parse.y:668
 		{
 			start := yyDollar[1].string.Start.add("'")
 			if yyDollar[1].string.TripleQuote {
@@ -1492,9 +1518,9 @@ yydefault:
 				},
 			}
 		}
-	case 63:
+	case 65:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:669
+//line build/parse.y:685
 		{
 			start := yyDollar[3].string.Start.add("'")
 			if yyDollar[3].string.TripleQuote {
@@ -1511,51 +1537,51 @@ yydefault:
 				to: *yyDollar[1].expr.(*Ident),
 			}
 		}
-	case 64:
+	case 66:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:684
+//line build/parse.y:700
 		{
 			yyVAL.exprs = nil
 		}
-	case 65:
+	case 67:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:688
+//line build/parse.y:704
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 		}
-	case 66:
+	case 68:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:694
+//line build/parse.y:710
 		{
 			yyVAL.exprs = []Expr{yyDollar[1].expr}
 		}
-	case 67:
+	case 69:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:698
+//line build/parse.y:714
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
 		}
-	case 69:
+	case 71:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:705
+//line build/parse.y:721
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
-	case 70:
+	case 72:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:709
+//line build/parse.y:725
 		{
 			yyVAL.expr = unary(yyDollar[1].pos, yyDollar[1].tok, yyDollar[2].expr)
 		}
-	case 71:
+	case 73:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:713
+//line build/parse.y:729
 		{
 			yyVAL.expr = unary(yyDollar[1].pos, yyDollar[1].tok, yyDollar[2].expr)
 		}
-	case 73:
+	case 75:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:720
+//line build/parse.y:736
 		{
 			tuple, ok := yyDollar[1].expr.(*TupleExpr)
 			if !ok || !tuple.NoBrackets {
@@ -1569,39 +1595,39 @@ yydefault:
 			tuple.List = append(tuple.List, yyDollar[3].expr)
 			yyVAL.expr = tuple
 		}
-	case 74:
+	case 76:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:735
+//line build/parse.y:751
 		{
 			yyVAL.expr = nil
 		}
-	case 76:
+	case 78:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:742
+//line build/parse.y:758
 		{
 			yyVAL.exprs = []Expr{yyDollar[1].expr}
 		}
-	case 77:
+	case 79:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:746
+//line build/parse.y:762
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
 		}
-	case 78:
+	case 80:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:751
+//line build/parse.y:767
 		{
 			yyVAL.exprs = nil
 		}
-	case 79:
+	case 81:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:755
+//line build/parse.y:771
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 		}
-	case 81:
+	case 83:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:762
+//line build/parse.y:778
 		{
 			yyVAL.expr = &LambdaExpr{
 				Function: Function{
@@ -1611,117 +1637,117 @@ yydefault:
 				},
 			}
 		}
-	case 82:
+	case 84:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:771
+//line build/parse.y:787
 		{
 			yyVAL.expr = unary(yyDollar[1].pos, yyDollar[1].tok, yyDollar[2].expr)
 		}
-	case 83:
+	case 85:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:772
+//line build/parse.y:788
 		{
 			yyVAL.expr = unary(yyDollar[1].pos, yyDollar[1].tok, yyDollar[2].expr)
 		}
-	case 84:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:773
-		{
-			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
-		}
-	case 85:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:774
-		{
-			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
-		}
 	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:775
+//line build/parse.y:789
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 87:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:776
+//line build/parse.y:790
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 88:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:777
+//line build/parse.y:791
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 89:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:778
+//line build/parse.y:792
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:779
+//line build/parse.y:793
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 91:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:780
+//line build/parse.y:794
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 92:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:781
+//line build/parse.y:795
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:782
+//line build/parse.y:796
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 94:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:783
+//line build/parse.y:797
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:784
+//line build/parse.y:798
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 96:
-		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:785
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line build/parse.y:799
 		{
-			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, "not in", yyDollar[4].expr)
+			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 97:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:786
+//line build/parse.y:800
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 98:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:787
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line build/parse.y:801
 		{
-			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
+			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, "not in", yyDollar[4].expr)
 		}
 	case 99:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:788
+//line build/parse.y:802
 		{
 			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 		}
 	case 100:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:790
+//line build/parse.y:803
+		{
+			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
+		}
+	case 101:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line build/parse.y:804
+		{
+			yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
+		}
+	case 102:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line build/parse.y:806
 		{
 			if b, ok := yyDollar[3].expr.(*UnaryExpr); ok && b.Op == "not" {
 				yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, "is not", b.X)
@@ -1729,9 +1755,9 @@ yydefault:
 				yyVAL.expr = binary(yyDollar[1].expr, yyDollar[2].pos, yyDollar[2].tok, yyDollar[3].expr)
 			}
 		}
-	case 101:
+	case 103:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line build/parse.y:798
+//line build/parse.y:814
 		{
 			yyVAL.expr = &ConditionalExpr{
 				Then:      yyDollar[1].expr,
@@ -1741,45 +1767,45 @@ yydefault:
 				Else:      yyDollar[5].expr,
 			}
 		}
-	case 102:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:810
+//line build/parse.y:826
 		{
 			yyVAL.exprs = []Expr{yyDollar[1].expr}
 		}
-	case 103:
+	case 105:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:814
+//line build/parse.y:830
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
 		}
-	case 104:
+	case 106:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:819
+//line build/parse.y:835
 		{
 			yyVAL.expr = nil
 		}
-	case 106:
+	case 108:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:825
+//line build/parse.y:841
 		{
 			yyVAL.exprs, yyVAL.comma = nil, Position{}
 		}
-	case 107:
+	case 109:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:829
+//line build/parse.y:845
 		{
 			yyVAL.exprs, yyVAL.comma = yyDollar[1].exprs, yyDollar[2].pos
 		}
-	case 108:
+	case 110:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:838
+//line build/parse.y:854
 		{
 			yyVAL.pos = Position{}
 		}
-	case 110:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:844
+//line build/parse.y:860
 		{
 			yyVAL.expr = &KeyValueExpr{
 				Key:   yyDollar[1].expr,
@@ -1787,39 +1813,39 @@ yydefault:
 				Value: yyDollar[3].expr,
 			}
 		}
-	case 111:
+	case 113:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:854
+//line build/parse.y:870
 		{
 			yyVAL.exprs = []Expr{yyDollar[1].expr}
 		}
-	case 112:
+	case 114:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:858
+//line build/parse.y:874
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
 		}
-	case 113:
+	case 115:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line build/parse.y:863
+//line build/parse.y:879
 		{
 			yyVAL.exprs = nil
 		}
-	case 114:
+	case 116:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:867
+//line build/parse.y:883
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 		}
-	case 115:
+	case 117:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:871
+//line build/parse.y:887
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 		}
-	case 117:
+	case 119:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:878
+//line build/parse.y:894
 		{
 			tuple, ok := yyDollar[1].expr.(*TupleExpr)
 			if !ok || !tuple.NoBrackets {
@@ -1833,9 +1859,9 @@ yydefault:
 			tuple.List = append(tuple.List, yyDollar[3].expr)
 			yyVAL.expr = tuple
 		}
-	case 118:
+	case 120:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:894
+//line build/parse.y:910
 		{
 			yyVAL.string = &StringExpr{
 				Start:       yyDollar[1].pos,
@@ -1845,33 +1871,33 @@ yydefault:
 				Token:       yyDollar[1].tok,
 			}
 		}
-	case 119:
+	case 121:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:906
+//line build/parse.y:922
 		{
 			yyVAL.strings = []*StringExpr{yyDollar[1].string}
 		}
-	case 120:
+	case 122:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:910
+//line build/parse.y:926
 		{
 			yyVAL.strings = append(yyDollar[1].strings, yyDollar[2].string)
 		}
-	case 121:
+	case 123:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:916
+//line build/parse.y:932
 		{
 			yyVAL.expr = &Ident{NamePos: yyDollar[1].pos, Name: yyDollar[1].tok}
 		}
-	case 122:
+	case 124:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:922
+//line build/parse.y:938
 		{
 			yyVAL.expr = &LiteralExpr{Start: yyDollar[1].pos, Token: yyDollar[1].tok}
 		}
-	case 123:
+	case 125:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line build/parse.y:928
+//line build/parse.y:944
 		{
 			yyVAL.expr = &ForClause{
 				For:  yyDollar[1].pos,
@@ -1880,30 +1906,30 @@ yydefault:
 				X:    yyDollar[4].expr,
 			}
 		}
-	case 124:
+	case 126:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:938
+//line build/parse.y:954
 		{
 			yyVAL.exprs = []Expr{yyDollar[1].expr}
 		}
-	case 125:
+	case 127:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line build/parse.y:941
+//line build/parse.y:957
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, &IfClause{
 				If:   yyDollar[2].pos,
 				Cond: yyDollar[3].expr,
 			})
 		}
-	case 126:
+	case 128:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line build/parse.y:950
+//line build/parse.y:966
 		{
 			yyVAL.exprs = yyDollar[1].exprs
 		}
-	case 127:
+	case 129:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line build/parse.y:953
+//line build/parse.y:969
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[2].exprs...)
 		}
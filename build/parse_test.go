@@ -18,6 +18,7 @@ package build
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -70,6 +71,212 @@ func TestParseTestdata(t *testing.T) {
 	}
 }
 
+func TestParseSemicolonSeparatedStatements(t *testing.T) {
+	f, err := ParseBzl("test.bzl", []byte("a = 1; b = 2  # comment\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Stmt) != 2 {
+		t.Fatalf("got %d statements, want 2", len(f.Stmt))
+	}
+	for i, name := range []string{"a", "b"} {
+		assign, ok := f.Stmt[i].(*AssignExpr)
+		if !ok {
+			t.Errorf("statement #%d: got %T, want *AssignExpr", i, f.Stmt[i])
+			continue
+		}
+		if lhs, ok := assign.LHS.(*Ident); !ok || lhs.Name != name {
+			t.Errorf("statement #%d: got LHS %q, want %q", i, FormatString(assign.LHS), name)
+		}
+	}
+	if suffix := f.Stmt[0].Comment().Suffix; len(suffix) != 0 {
+		t.Errorf("first statement: got suffix comments %v, want none", suffix)
+	}
+	if suffix := f.Stmt[1].Comment().Suffix; len(suffix) != 1 || suffix[0].Token != "# comment" {
+		t.Errorf("second statement: got suffix comments %v, want [# comment]", suffix)
+	}
+}
+
+func TestParseAssertAsName(t *testing.T) {
+	src := `load("assert.star", "assert")
+
+assert.eq(1, 1)
+assert(x)
+assert = 1
+assert[0] == 1
+assert x == 1
+`
+	f, err := ParseBzl("test.bzl", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"*build.LoadStmt", "*build.CallExpr", "*build.CallExpr", "*build.AssignExpr", "*build.BinaryExpr", "*build.AssertStmt"}
+	if len(f.Stmt) != len(want) {
+		t.Fatalf("got %d statements, want %d", len(f.Stmt), len(want))
+	}
+	for i, stmt := range f.Stmt {
+		if got := fmt.Sprintf("%T", stmt); got != want[i] {
+			t.Errorf("statement #%d: got %s, want %s", i, got, want[i])
+		}
+	}
+	if out := string(Format(f)); out != src {
+		t.Errorf("Format() = %q, want %q", out, src)
+	}
+}
+
+func TestParseAssertInExpression(t *testing.T) {
+	// `assert` only starts an assert statement at the beginning of a statement.
+	for _, src := range []string{
+		"y = assert + 1\n",
+		"y = assert - 1\n",
+		"z = assert and b\n",
+		"z = assert if c else d\n",
+		"for assert in xs:\n    pass\n",
+	} {
+		f, err := ParseBzl("test.bzl", []byte(src))
+		if err != nil {
+			t.Errorf("ParseBzl(%q): %v", src, err)
+			continue
+		}
+		if out := string(Format(f)); out != src {
+			t.Errorf("Format(%q) = %q", src, out)
+		}
+	}
+}
+
+func TestParseTrailingComma(t *testing.T) {
+	f, err := ParseBuild("BUILD", []byte(`foo(
+    name = "foo",
+    srcs = [
+        "a",  # comment
+    ],
+    deps = [
+        "b"
+    ],
+    env = {"a": "b",},
+    tags = ["c", # comment
+    ]
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := f.Stmt[0].(*CallExpr)
+	tests := []struct {
+		x    Expr
+		want bool
+	}{
+		{call, false},
+		{call.Attr("srcs"), true},
+		{call.Attr("deps"), false},
+		{call.Attr("env"), true},
+		{call.Attr("tags"), true},
+		{NewList(NewString("a")), false},
+	}
+	for _, tt := range tests {
+		if got := f.HasTrailingComma(tt.x); got != tt.want {
+			t.Errorf("HasTrailingComma(%s) = %v, want %v", FormatString(tt.x), got, tt.want)
+		}
+	}
+}
+
+func TestParseTuples(t *testing.T) {
+	tests := []struct {
+		in    string
+		tuple bool
+		out   string
+	}{
+		{"x = ()\n", true, "x = ()\n"},
+		{"x = (a,)\n", true, "x = (a,)\n"},
+		{"x = ( a , )\n", true, "x = (a,)\n"},
+		{"x = (a, b)\n", true, "x = (a, b)\n"},
+		{"x = (a, b,)\n", true, "x = (a, b)\n"},
+		{"x = (a)\n", false, "x = (a)\n"},
+	}
+	for _, tt := range tests {
+		f, err := ParseBzl("test.bzl", []byte(tt.in))
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		rhs := f.Stmt[0].(*AssignExpr).RHS
+		if _, ok := rhs.(*TupleExpr); ok != tt.tuple {
+			t.Errorf("%q: got %T, want a tuple: %v", tt.in, rhs, tt.tuple)
+		}
+		if got := string(Format(f)); got != tt.out {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestParseRecover(t *testing.T) {
+	tests := []struct {
+		in     string
+		stmts  []string
+		lines  []int
+		errors []int
+	}{
+		{
+			in: `a = 1
+
+foo(
+    name = "x",,
+)
+
+b = 2
+`,
+			stmts:  []string{"a = 1", "b = 2"},
+			lines:  []int{1, 7},
+			errors: []int{4},
+		},
+		{
+			in: `def f(:
+    return 1
+
+def g(x):
+    if x:
+        return x
+    else:
+        return -x
+
+h(
+`,
+			stmts:  []string{"def g(x):\n    if x:\n        return x\n    else:\n        return -x"},
+			lines:  []int{4},
+			errors: []int{1, 12},
+		},
+		{
+			in:    "a = 1\n",
+			stmts: []string{"a = 1"},
+			lines: []int{1},
+		},
+	}
+	for i, tt := range tests {
+		f, errs := ParseRecover("test.bzl", []byte(tt.in))
+		if f == nil {
+			t.Errorf("#%d: ParseRecover() returned no file", i)
+			continue
+		}
+		var stmts []string
+		var lines []int
+		for _, stmt := range f.Stmt {
+			stmts = append(stmts, FormatString(stmt))
+			start, _ := stmt.Span()
+			lines = append(lines, start.Line)
+		}
+		if !reflect.DeepEqual(stmts, tt.stmts) || !reflect.DeepEqual(lines, tt.lines) {
+			t.Errorf("#%d: got statements %q at lines %v, want %q at lines %v", i, stmts, lines, tt.stmts, tt.lines)
+		}
+		var errLines []int
+		for _, err := range errs {
+			errLines = append(errLines, err.Pos.Line)
+		}
+		if !reflect.DeepEqual(errLines, tt.errors) {
+			t.Errorf("#%d: got errors %v, want errors at lines %v", i, errs, tt.errors)
+		}
+	}
+}
+
 // toJSON returns human-readable json for the given syntax tree.
 // It is used as input to diff for comparing the actual syntax tree with the expected one.
 func toJSON(v interface{}) string {
@@ -29,6 +29,36 @@ const (
 	defIndentation    = 8 // Indentation of multiline function definitions
 )
 
+// Spacing describes how top-level statements of BUILD and WORKSPACE files are separated.
+type Spacing int
+
+const (
+	// SpacingSeparated separates all top-level statements by a blank line.
+	SpacingSeparated Spacing = iota
+	// SpacingGrouped keeps consecutive top-level statements together if they weren't
+	// separated by a blank line in the original file.
+	SpacingGrouped
+)
+
+// StatementSpacing is the spacing policy used for top-level statements of BUILD and WORKSPACE files.
+// Statements in .bzl and generic Starlark files always keep their original grouping.
+var StatementSpacing = SpacingSeparated
+
+// AlignAssignments enables aligning of the "=" signs of consecutive single-line assignments
+// to plain variables, such as
+//
+//     a   = 1
+//     bcd = 2
+//
+// A blank line or a comment breaks the run of aligned assignments. Keyword arguments
+// of function calls are never aligned.
+var AlignAssignments = false
+
+// PreserveComments disables the normalization of comments: instead of trimming the whitespace
+// around the text of each comment, the printer emits it as it was written in the source file,
+// only dropping a trailing carriage return. Comments are still re-indented.
+var PreserveComments = false
+
 // Format returns the formatted form of the given BUILD or bzl file.
 func Format(f *File) []byte {
 	pr := &printer{fileType: f.Type}
@@ -62,6 +92,8 @@ type printer struct {
 	depth        int       // nesting depth inside ( ) [ ] { }
 	level        int       // nesting level of def-, if-else- and for-blocks
 	needsNewLine bool      // true if the next statement needs a new line before it
+	alignWidth   int       // width the LHS of the next printed assignment should be padded to
+	keepUntil    int       // length of the output that trim must keep, see PreserveComments
 }
 
 // printf prints to the buffer.
@@ -93,7 +125,7 @@ func (p *printer) newline() {
 				p.trim()
 				p.printf("\n%*s", p.margin, "")
 			}
-			p.printf("%s", strings.TrimSpace(com.Token))
+			p.printComment(com)
 		}
 		p.comment = p.comment[:0]
 	}
@@ -142,23 +174,33 @@ func (p *printer) trim() {
 	// Remove trailing space from line we're about to end.
 	b := p.Bytes()
 	n := len(b)
-	for n > 0 && b[n-1] == ' ' {
+	for n > p.keepUntil && b[n-1] == ' ' {
 		n--
 	}
 	p.Truncate(n)
 }
 
+// printComment prints the text of a comment.
+func (p *printer) printComment(com Comment) {
+	if !PreserveComments {
+		p.printf("%s", strings.TrimSpace(com.Token))
+		return
+	}
+	p.printf("%s", strings.TrimSuffix(com.Token, "\r"))
+	p.keepUntil = p.Len()
+}
+
 // file formats the given file into the print buffer.
 func (p *printer) file(f *File) {
 	for _, com := range f.Before {
-		p.printf("%s", strings.TrimSpace(com.Token))
+		p.printComment(com)
 		p.newline()
 	}
 
 	p.statements(f.Stmt)
 
 	for _, com := range f.After {
-		p.printf("%s", strings.TrimSpace(com.Token))
+		p.printComment(com)
 		p.newline()
 	}
 
@@ -177,12 +219,19 @@ func (p *printer) nestedStatements(stmts []Expr) {
 }
 
 func (p *printer) statements(stmts []Expr) {
+	var widths []int
+	if AlignAssignments {
+		widths = p.assignmentWidths(stmts)
+	}
 	for i, stmt := range stmts {
 		switch stmt := stmt.(type) {
 		case *CommentBlock:
 			// comments already handled
 
 		default:
+			if widths != nil {
+				p.alignWidth = widths[i]
+			}
 			p.expr(stmt, precLow)
 		}
 
@@ -194,7 +243,7 @@ func (p *printer) statements(stmts []Expr) {
 
 		for _, com := range stmt.Comment().After {
 			p.newlineIfNeeded()
-			p.printf("%s", strings.TrimSpace(com.Token))
+			p.printComment(com)
 			p.softNewline()
 		}
 
@@ -210,6 +259,52 @@ func (p *printer) statements(stmts []Expr) {
 	}
 }
 
+// alignableAssignment returns the name of the variable if x is a single-line assignment
+// to a plain variable, or an empty string otherwise.
+func alignableAssignment(x Expr) string {
+	as, ok := x.(*AssignExpr)
+	if !ok || as.Op != "=" || as.LineBreak {
+		return ""
+	}
+	ident, ok := as.LHS.(*Ident)
+	if !ok {
+		return ""
+	}
+	if start, end := as.Span(); start.Line != end.Line {
+		return ""
+	}
+	return ident.Name
+}
+
+// assignmentWidths returns for each of the statements the width the variable name
+// should be padded to, so that the "=" signs of runs of consecutive assignments are aligned.
+func (p *printer) assignmentWidths(stmts []Expr) []int {
+	widths := make([]int, len(stmts))
+	for i := 0; i < len(stmts); {
+		if alignableAssignment(stmts[i]) == "" {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(stmts) && alignableAssignment(stmts[j]) != "" && p.compactStmt(stmts[j-1], stmts[j]) {
+			j++
+		}
+		if j-i > 1 {
+			width := 0
+			for _, stmt := range stmts[i:j] {
+				if n := len(alignableAssignment(stmt)); n > width {
+					width = n
+				}
+			}
+			for k := i; k < j; k++ {
+				widths[k] = width
+			}
+		}
+		i = j
+	}
+	return widths
+}
+
 // compactStmt reports whether the pair of statements s1, s2
 // should be printed without an intervening blank line.
 // We omit the blank line when both are subinclude statements
@@ -226,7 +321,7 @@ func (p *printer) compactStmt(s1, s2 Expr) bool {
 	} else if isCommentBlock(s1) || isCommentBlock(s2) {
 		// Standalone comment blocks shouldn't be attached to other statements
 		return false
-	} else if (p.fileType == TypeBuild || p.fileType == TypeWorkspace) && p.level == 0 {
+	} else if (p.fileType == TypeBuild || p.fileType == TypeWorkspace) && p.level == 0 && StatementSpacing == SpacingSeparated {
 		// Top-level statements in a BUILD or WORKSPACE file
 		return false
 	} else if isFunctionDefinition(s1) || isFunctionDefinition(s2) {
@@ -356,7 +451,7 @@ func (p *printer) expr(v Expr, outerPrec int) {
 		// Re-indent to margin.
 		p.printf("%*s", p.margin, "")
 		for _, com := range before {
-			p.printf("%s", strings.TrimSpace(com.Token))
+			p.printComment(com)
 			p.newline()
 		}
 	}
@@ -511,6 +606,12 @@ func (p *printer) expr(v Expr, outerPrec int) {
 		}
 
 		p.expr(v.LHS, precAssign)
+		if p.alignWidth > 0 {
+			if ident, ok := v.LHS.(*Ident); ok {
+				p.printf("%*s", p.alignWidth-len(ident.Name), "")
+			}
+			p.alignWidth = 0
+		}
 		p.printf(" %s", v.Op)
 		if v.LineBreak {
 			p.breakline()
@@ -591,6 +692,14 @@ func (p *printer) expr(v Expr, outerPrec int) {
 			p.expr(v.Result, precLow)
 		}
 
+	case *AssertStmt:
+		p.printf("assert ")
+		p.expr(v.Cond, precLow)
+		if v.Message != nil {
+			p.printf(", ")
+			p.expr(v.Message, precLow)
+		}
+
 	case *DefStmt:
 		p.printf("def ")
 		p.printf(v.Name)
@@ -815,7 +924,7 @@ func (p *printer) seq(brack string, start *Position, list *[]Expr, end *End, mod
 	if end != nil {
 		for _, com := range end.Before {
 			p.newline()
-			p.printf("%s", strings.TrimSpace(com.Token))
+			p.printComment(com)
 		}
 	}
 	p.margin -= indentation
@@ -889,7 +998,7 @@ func (p *printer) listFor(v *Comprehension) {
 	if multiLine {
 		for _, com := range v.End.Before {
 			p.newline()
-			p.printf("%s", strings.TrimSpace(com.Token))
+			p.printComment(com)
 		}
 		p.margin -= listIndentation
 		p.newline()
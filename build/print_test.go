@@ -467,6 +467,8 @@ func (eq *eqchecker) checkValue(v, w reflect.Value) error {
 			case tf.Name == "MultiLine": // ignore multiline setting
 			case tf.Name == "LineBreak": // ignore line break setting
 			case t == stringExprType && tf.Name == "Token": // ignore raw string token
+			case tf.Name == "annotations": // ignore metadata not produced by the parser
+			case tf.Name == "trailingCommas": // ignore trailing commas, the printer adds them
 			}
 		}
 
@@ -483,3 +485,535 @@ func (eq *eqchecker) checkValue(v, w reflect.Value) error {
 	}
 	return nil
 }
+
+func TestPrintStatementSpacing(t *testing.T) {
+	input := `cc_library(name = "a")
+cc_library(name = "b")
+
+cc_library(name = "c")
+`
+	tests := []struct {
+		spacing Spacing
+		want    string
+	}{
+		{SpacingSeparated, `cc_library(name = "a")
+
+cc_library(name = "b")
+
+cc_library(name = "c")
+`},
+		{SpacingGrouped, `cc_library(name = "a")
+cc_library(name = "b")
+
+cc_library(name = "c")
+`},
+	}
+
+	defer func() { StatementSpacing = SpacingSeparated }()
+	for _, tt := range tests {
+		StatementSpacing = tt.spacing
+		f, err := ParseBuild("BUILD", []byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(Format(f)); got != tt.want {
+			t.Errorf("Format() with spacing %d: diff shows -want, +got", tt.spacing)
+			testutils.Tdiff(t, []byte(tt.want), []byte(got))
+		}
+	}
+}
+
+func TestPrintAlignAssignments(t *testing.T) {
+	input := `a = 1
+bcd = 2
+ef = foo(x = 1, yyy = 2)
+
+g = 3
+hi = 4
+# comment
+jkl = 5
+m.n = 6
+o += 7
+
+def f():
+    x = 1
+    yyy = [
+        1,
+    ]
+    zz = 2
+    zzz = 3
+`
+	aligned := `a   = 1
+bcd = 2
+ef  = foo(x = 1, yyy = 2)
+
+g  = 3
+hi = 4
+
+# comment
+jkl = 5
+m.n = 6
+o += 7
+
+def f():
+    x = 1
+    yyy = [
+        1,
+    ]
+    zz  = 2
+    zzz = 3
+`
+	tests := []struct {
+		alignAssignments bool
+		want             string
+	}{
+		{false, strings.Replace(input, "hi = 4\n", "hi = 4\n\n", 1)},
+		{true, aligned},
+	}
+
+	defer func() { AlignAssignments = false }()
+	for _, tt := range tests {
+		AlignAssignments = tt.alignAssignments
+		f, err := ParseBzl("test.bzl", []byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(Format(f)); got != tt.want {
+			t.Errorf("Format() with AlignAssignments = %v: diff shows -want, +got", tt.alignAssignments)
+			testutils.Tdiff(t, []byte(tt.want), []byte(got))
+		}
+	}
+}
+
+func TestPrintPreserveComments(t *testing.T) {
+	input := "#    Header   with   gaps    \n" +
+		"\n" +
+		"foo(\n" +
+		"    #  before\t\n" +
+		"    name = \"x\",  #suffix   \n" +
+		"    #   after  \n" +
+		")\n"
+	normalized := "#    Header   with   gaps\n" +
+		"\n" +
+		"foo(\n" +
+		"    #  before\n" +
+		"    name = \"x\",  #suffix\n" +
+		"    #   after\n" +
+		")\n"
+	tests := []struct {
+		preserveComments bool
+		want             string
+	}{
+		{false, normalized},
+		{true, input},
+	}
+
+	defer func() { PreserveComments = false }()
+	for _, tt := range tests {
+		PreserveComments = tt.preserveComments
+		f, err := ParseBuild("BUILD", []byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(Format(f)); got != tt.want {
+			t.Errorf("Format() with PreserveComments = %v: diff shows -want, +got", tt.preserveComments)
+			testutils.Tdiff(t, []byte(tt.want), []byte(got))
+		}
+	}
+}
+
+func TestCompareListElements(t *testing.T) {
+	tests := []struct {
+		a, b Expr
+		want int
+	}{
+		{NewString("a"), NewString("b"), -1},
+		{NewString("b"), NewString("a"), 1},
+		{NewString("a"), NewString("a"), 0},
+		{NewString("z"), NewString(":a"), -1},
+		{NewString(":z"), NewString("//a"), -1},
+		{NewString("//z"), NewString("@a"), -1},
+		{NewString("//a:b"), NewString("//a.b"), 1},
+		{NewString("//a"), NewString("//a:a"), -1},
+		{NewString("//a:b"), NewString("//a/b"), -1},
+		{&LiteralExpr{Token: "1"}, NewString("a"), -1},
+		{NewString("a"), &LiteralExpr{Token: "1"}, 1},
+		{&LiteralExpr{Token: "1"}, &Ident{Name: "x"}, 0},
+	}
+	for _, tt := range tests {
+		if got := CompareListElements(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareListElements(%s, %s) = %d, want %d", FormatString(tt.a), FormatString(tt.b), got, tt.want)
+		}
+	}
+}
+
+func TestSortStringList(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`["b", "a", X, "d", "c", "a"]`, `["a", "b", X, "a", "c", "d"]`},
+		{`["a", "b", X, "c"]`, `["a", "b", X, "c"]`},
+		{`["b", "b", "a"]`, `["a", "b"]`},
+	}
+	for _, tt := range tests {
+		f, err := ParseBuild("BUILD", []byte(tt.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		list := f.Stmt[0].(*ListExpr)
+		SortStringList(list)
+		var elems []string
+		for _, x := range list.List {
+			elems = append(elems, FormatString(x))
+		}
+		if got := "[" + strings.Join(elems, ", ") + "]"; got != tt.want {
+			t.Errorf("SortStringList(%s): got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestRewriteSortOrder pins the order of sorted lists, it must not change between versions.
+func TestRewriteSortOrder(t *testing.T) {
+	input := `cc_library(
+    name = "foo",
+    deps = [
+        # keep sorted
+        "@repo//foo",
+        "//foo:bar",
+        "//foo/bar",
+        "//foo",
+        ":foo.bar",
+        ":foo",
+        "foo.cc",
+        "Foo.cc",
+        "foo",
+        42,
+        "//b",
+        "//a",
+        "//a",
+        # comment
+        "@b",
+        "@a//:a",
+        ":z",  # suffix
+        "//z",
+    ],
+)
+`
+	want := `cc_library(
+    name = "foo",
+    deps = [
+        # keep sorted
+        "Foo.cc",
+        "foo",
+        "foo.cc",
+        ":foo",
+        ":foo.bar",
+        "//foo",
+        "//foo:bar",
+        "//foo/bar",
+        "@repo//foo",
+        42,
+        "//a",
+        "//b",
+        # comment
+        ":z",  # suffix
+        "//z",
+        "@a",
+        "@b",
+    ],
+)
+`
+	f, err := ParseBuild("BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Rewrite(f, nil)
+	if got := string(Format(f)); got != want {
+		t.Errorf("Rewrite(): diff shows -want, +got")
+		testutils.Tdiff(t, []byte(want), []byte(got))
+	}
+}
+
+func TestRewriteSortDictKeys(t *testing.T) {
+	input := `cc_test(
+    name = "a",
+    env = {
+        "PATH": "/bin",
+        "HOME": "/home",
+        # Locale
+        "LC_CTYPE": "C",
+        "LANG": "C",  # suffix
+    },
+    # do not sort
+    tags = {
+        "z": "1",
+        "y": "2",
+    },
+    deps = select({
+        "//conditions:b": [":b"],
+        "//conditions:a": [":a"],
+        "//conditions:default": [],
+    }),
+)
+`
+	sorted := `cc_test(
+    name = "a",
+    env = {
+        "HOME": "/home",
+        "PATH": "/bin",
+        # Locale
+        "LANG": "C",  # suffix
+        "LC_CTYPE": "C",
+    },
+    # do not sort
+    tags = {
+        "z": "1",
+        "y": "2",
+    },
+    deps = select({
+        "//conditions:b": [":b"],
+        "//conditions:a": [":a"],
+        "//conditions:default": [],
+    }),
+)
+`
+	tests := []struct {
+		sortDictKeys bool
+		want         string
+	}{
+		{false, input},
+		{true, sorted},
+	}
+
+	defer func() { SortDictKeys = false }()
+	for _, tt := range tests {
+		SortDictKeys = tt.sortDictKeys
+		f, err := ParseBuild("BUILD", []byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		Rewrite(f, nil)
+		if got := string(Format(f)); got != tt.want {
+			t.Errorf("Rewrite() with SortDictKeys = %v: diff shows -want, +got", tt.sortDictKeys)
+			testutils.Tdiff(t, []byte(tt.want), []byte(got))
+		}
+	}
+}
+
+func TestSortAllLists(t *testing.T) {
+	input := `# keep sorted
+ALL_SRCS = [
+    "z.cc",
+    "a.cc",
+]
+
+cc_library(
+    name = "a",
+    srcs = [
+        "b.cc",
+        "a.cc",
+    ],
+    # do not sort
+    hdrs = [
+        "b.h",
+        "a.h",
+    ],
+    # buildifier: leave-alone
+    deps = [
+        ":b",
+        ":a",
+    ],
+    copts = [
+        "-Wall",
+        "-O2",
+    ],
+)
+`
+	golden := `# keep sorted
+ALL_SRCS = [
+    "a.cc",
+    "z.cc",
+]
+
+cc_library(
+    name = "a",
+    srcs = [
+        "a.cc",
+        "b.cc",
+    ],
+    # do not sort
+    hdrs = [
+        "b.h",
+        "a.h",
+    ],
+    # buildifier: leave-alone
+    deps = [
+        ":b",
+        ":a",
+    ],
+    copts = [
+        "-Wall",
+        "-O2",
+    ],
+)
+`
+	f, err := ParseBuild("BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.SortAllLists() {
+		t.Errorf("SortAllLists() = false, want true")
+	}
+	if got := string(Format(f)); got != golden {
+		t.Errorf("SortAllLists(): diff shows -want, +got")
+		testutils.Tdiff(t, []byte(golden), []byte(got))
+	}
+	if f.SortAllLists() {
+		t.Errorf("second SortAllLists() = true, want false")
+	}
+}
+
+func TestMoveComments(t *testing.T) {
+	f, err := ParseBuild("BUILD", []byte(`# The old library.
+cc_library(name = "old")  # deprecated
+
+cc_binary(name = "bin")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the library with a new one keeping its comments
+	replacement := &CallExpr{
+		X:    NewIdent("cc_library"),
+		List: []Expr{&AssignExpr{LHS: NewIdent("name"), Op: "=", RHS: NewString("new")}},
+	}
+	MoveComments(f.Stmt[0], replacement)
+	if comments := f.Stmt[0].Comment(); len(comments.Before) != 0 || len(comments.Suffix) != 0 {
+		t.Errorf("comments of the original node haven't been removed: %v", comments)
+	}
+	f.Stmt[0] = replacement
+
+	want := `# The old library.
+cc_library(name = "new")  # deprecated
+
+cc_binary(name = "bin")
+`
+	if got := string(Format(f)); got != want {
+		t.Errorf("Format() after MoveComments: diff shows -want, +got")
+		testutils.Tdiff(t, []byte(want), []byte(got))
+	}
+
+	ClearComments(f.Stmt[0])
+	want = `cc_library(name = "new")
+
+cc_binary(name = "bin")
+`
+	if got := string(Format(f)); got != want {
+		t.Errorf("Format() after ClearComments: diff shows -want, +got")
+		testutils.Tdiff(t, []byte(want), []byte(got))
+	}
+}
+
+func TestPrintConstructedNodes(t *testing.T) {
+	attr := func(name string, value Expr) Expr {
+		return &AssignExpr{LHS: NewIdent(name), Op: "=", RHS: value}
+	}
+	f := &File{
+		Type: TypeBuild,
+		Stmt: []Expr{
+			&CallExpr{
+				X: NewIdent("cc_library"),
+				List: []Expr{
+					attr("name", NewString("foo")),
+					attr("srcs", NewList(NewString("foo.cc"), NewString(`say "hi".cc`))),
+					attr("deps", NewList()),
+					attr("defines", NewDict(
+						[2]Expr{NewString("A"), NewString("1")},
+						[2]Expr{NewString("B"), NewIdent("B_VALUE")},
+					)),
+				},
+			},
+		},
+	}
+	want := `cc_library(
+    name = "foo",
+    srcs = [
+        "foo.cc",
+        "say \"hi\".cc",
+    ],
+    deps = [],
+    defines = {
+        "A": "1",
+        "B": B_VALUE,
+    },
+)
+`
+	if got := string(Format(f)); got != want {
+		t.Error("diff shows -want, +got")
+		testutils.Tdiff(t, []byte(want), []byte(got))
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	input := `cc_library(name = "foo")
+
+cc_library(name = "bar")
+`
+	f, err := ParseBuild("BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	foo, bar := f.Stmt[0], f.Stmt[1]
+	f.SetAnnotation(foo, "generated", true)
+	f.SetAnnotation(foo, "owner", "someone")
+
+	if got := string(Format(f)); got != input {
+		t.Errorf("Format() with annotations = %q, want %q", got, input)
+	}
+
+	if val, ok := f.GetAnnotation(foo, "generated"); !ok || val != true {
+		t.Errorf(`GetAnnotation(foo, "generated") = (%v, %v), want (true, true)`, val, ok)
+	}
+	if val, ok := f.GetAnnotation(foo, "owner"); !ok || val != "someone" {
+		t.Errorf(`GetAnnotation(foo, "owner") = (%v, %v), want ("someone", true)`, val, ok)
+	}
+	if val, ok := f.GetAnnotation(bar, "generated"); ok {
+		t.Errorf(`GetAnnotation(bar, "generated") = (%v, %v), want (nil, false)`, val, ok)
+	}
+	if val, ok := Copy(f).(*File).GetAnnotation(foo, "generated"); ok {
+		t.Errorf(`GetAnnotation() on a copy = (%v, %v), want (nil, false)`, val, ok)
+	}
+}
+
+// BenchmarkFormatUnchanged measures the formatting of a file that is already in the canonical form,
+// which is the most common case when formatting a whole repository.
+func BenchmarkFormatUnchanged(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString("load(\":defs.bzl\", \"java_rules\")\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&sb, `
+java_library(
+    name = "lib%d",
+    srcs = glob(["lib%d/*.java"]),
+    visibility = ["//visibility:public"],
+    deps = [
+        ":lib%d",
+        "//java/com/example:base",
+    ],
+)
+`, i, i, i+1)
+	}
+	data := []byte(sb.String())
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		f, err := ParseBuild("BUILD", data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		Rewrite(f, nil)
+		if out := Format(f); !bytes.Equal(out, data) {
+			b.Fatalf("the input isn't formatted:\n%s", out)
+		}
+	}
+}
@@ -117,6 +117,7 @@ var rewrites = []struct {
 	{"callsort", sortCallArgs, scopeBuild},
 	{"label", fixLabels, scopeBuild},
 	{"listsort", sortStringLists, scopeBoth},
+	{"dictsort", sortDictKeys, scopeBuild},
 	{"multiplus", fixMultilinePlus, scopeBuild},
 	{"loadsort", sortLoadArgs, scopeBoth},
 	{"formatdocstrings", formatDocstrings, scopeBoth},
@@ -124,6 +125,10 @@ var rewrites = []struct {
 	{"editoctal", editOctals, scopeBoth},
 }
 
+// SortDictKeys enables sorting of dict literals with string keys in BUILD files,
+// such as `env = {...}`. Dicts that are arguments of select() are never sorted.
+var SortDictKeys = false
+
 // DisableLoadSortForBuildFiles disables the loadsort transformation for BUILD files.
 // This is a temporary function for backward compatibility, can be called if there's plenty of
 // already formatted BUILD files that shouldn't be changed by the transformation.
@@ -490,7 +495,18 @@ func sortStringLists(f *File, info *RewriteInfo) {
 	})
 }
 
-// SortStringList sorts x, a list of strings.
+// SortAllLists sorts the lists of strings in the file the same way as Rewrite does,
+// without applying any other rewrites. The lists marked with "do not sort" or
+// "buildifier: leave-alone" comments are left as is, and the lists marked with
+// "keep sorted" are always sorted. Reports whether any of the lists has changed.
+func (f *File) SortAllLists() bool {
+	var info RewriteInfo
+	sortStringLists(f, &info)
+	return info.SortStringList > 0
+}
+
+// SortStringList sorts x, a list of strings, the same way as Buildifier does: each run
+// of consecutive strings is sorted with CompareListElements and deduplicated separately.
 func SortStringList(x Expr) {
 	sortStringList(x, nil, "")
 }
@@ -532,10 +548,11 @@ func sortStringList(x Expr, info *RewriteInfo, context string) {
 		}
 
 		var chunk []stringSortKey
-		for index, x := range list.List[i:j] {
-			chunk = append(chunk, makeSortKey(index, x.(*StringExpr)))
+		for _, x := range list.List[i:j] {
+			chunk = append(chunk, makeSortKey(x.(*StringExpr)))
 		}
-		if !sort.IsSorted(byStringExpr(chunk)) || !isUniq(chunk) {
+		less := func(a, b int) bool { return compareSortKeys(chunk[a], chunk[b]) < 0 }
+		if !sort.SliceIsSorted(chunk, less) || !isUniq(chunk) {
 			if info != nil {
 				info.SortStringList++
 				if !tables.SortableWhitelist[context] {
@@ -546,7 +563,7 @@ func sortStringList(x Expr, info *RewriteInfo, context string) {
 			before := chunk[0].x.Comment().Before
 			chunk[0].x.Comment().Before = nil
 
-			sort.Sort(byStringExpr(chunk))
+			sort.SliceStable(chunk, less)
 			chunk = uniq(chunk)
 
 			chunk[0].x.Comment().Before = before
@@ -582,6 +599,91 @@ func isUniq(list []stringSortKey) bool {
 	return true
 }
 
+// sortDictKeys sorts the entries of dict literals by their keys if SortDictKeys is set.
+func sortDictKeys(f *File, info *RewriteInfo) {
+	if !SortDictKeys {
+		return
+	}
+	Walk(f, func(v Expr, stk []Expr) {
+		dict, ok := v.(*DictExpr)
+		if !ok || len(dict.List) < 2 || leaveAlone(stk, dict) || doNotSort(dict) {
+			return
+		}
+		for _, x := range stk {
+			// "do not sort" comment on the attribute.
+			if as, ok := x.(*AssignExpr); ok && doNotSort(as) {
+				return
+			}
+		}
+		// The order of the conditions of select() is meaningful for readers,
+		// e.g. the default condition is usually the last one.
+		if n := len(stk); n > 0 {
+			if call, ok := stk[n-1].(*CallExpr); ok && callName(call) == "select" {
+				return
+			}
+		}
+		if kv, ok := dict.List[0].(*KeyValueExpr); ok && (doNotSort(kv) || doNotSort(kv.Key)) {
+			return
+		}
+		sortDictEntries(dict)
+	})
+}
+
+// sortDictEntries sorts the entries of a dict by their keys.
+// The dict is broken by non-string keys and by comments into chunks,
+// each chunk is sorted in place.
+func sortDictEntries(dict *DictExpr) {
+	stringKey := func(x Expr) (string, bool) {
+		kv, ok := x.(*KeyValueExpr)
+		if !ok {
+			return "", false
+		}
+		str, ok := kv.Key.(*StringExpr)
+		if !ok {
+			return "", false
+		}
+		return str.Value, true
+	}
+	hasBefore := func(x Expr) bool {
+		kv := x.(*KeyValueExpr)
+		return len(kv.Comment().Before) > 0 || len(kv.Key.Comment().Before) > 0
+	}
+
+	for i := 0; i < len(dict.List); {
+		if _, ok := stringKey(dict.List[i]); !ok {
+			i++
+			continue
+		}
+
+		j := i + 1
+		for ; j < len(dict.List); j++ {
+			if _, ok := stringKey(dict.List[j]); !ok || hasBefore(dict.List[j]) {
+				break
+			}
+		}
+
+		chunk := dict.List[i:j]
+		less := func(a, b int) bool {
+			keyA, _ := stringKey(chunk[a])
+			keyB, _ := stringKey(chunk[b])
+			return keyA < keyB
+		}
+		if !sort.SliceIsSorted(chunk, less) {
+			// The comments above the chunk stay in place.
+			first := chunk[0].(*KeyValueExpr)
+			before, keyBefore := first.Comment().Before, first.Key.Comment().Before
+			first.Comment().Before, first.Key.Comment().Before = nil, nil
+
+			sort.SliceStable(chunk, less)
+
+			first = chunk[0].(*KeyValueExpr)
+			first.Comment().Before, first.Key.Comment().Before = before, keyBefore
+		}
+
+		i = j
+	}
+}
+
 // If stk describes a call argument like rule(arg=...), callArgName
 // returns the name of that argument, formatted as "rule.arg".
 func callArgName(stk []Expr) string {
@@ -609,20 +711,19 @@ func callArgName(stk []Expr) string {
 // strings beginning with ":", strings beginning with "//", and strings
 // beginning with "@". The next significant part of the comparison is the list
 // of elements in the value, where elements are split at `.' and `:'. Finally
-// we compare by value and break ties by original index.
+// we compare by value. Ties are broken by the original order since the
+// sorting is stable.
 type stringSortKey struct {
-	phase    int
-	split    []string
-	value    string
-	original int
-	x        Expr
+	phase int
+	split []string
+	value string
+	x     Expr
 }
 
-func makeSortKey(index int, x *StringExpr) stringSortKey {
+func makeSortKey(x *StringExpr) stringSortKey {
 	key := stringSortKey{
-		value:    x.Value,
-		original: index,
-		x:        x,
+		value: x.Value,
+		x:     x,
 	}
 
 	switch {
@@ -638,31 +739,56 @@ func makeSortKey(index int, x *StringExpr) stringSortKey {
 	return key
 }
 
-// byStringExpr implements sort.Interface for a list of stringSortKey.
-type byStringExpr []stringSortKey
-
-func (x byStringExpr) Len() int      { return len(x) }
-func (x byStringExpr) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
-
-func (x byStringExpr) Less(i, j int) bool {
-	xi := x[i]
-	xj := x[j]
-
+// compareSortKeys compares two sort keys, it returns -1, 0 or 1 like strings.Compare.
+func compareSortKeys(xi, xj stringSortKey) int {
 	if xi.phase != xj.phase {
-		return xi.phase < xj.phase
+		return compareInts(xi.phase, xj.phase)
 	}
 	for k := 0; k < len(xi.split) && k < len(xj.split); k++ {
 		if xi.split[k] != xj.split[k] {
-			return xi.split[k] < xj.split[k]
+			return strings.Compare(xi.split[k], xj.split[k])
 		}
 	}
 	if len(xi.split) != len(xj.split) {
-		return len(xi.split) < len(xj.split)
+		return compareInts(len(xi.split), len(xj.split))
 	}
-	if xi.value != xj.value {
-		return xi.value < xj.value
+	return strings.Compare(xi.value, xj.value)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// CompareListElements compares two elements of a list in the order used by Buildifier to
+// sort lists of strings, it returns -1 if a goes before b, 1 if b goes before a, and 0 if
+// they are equal. Strings are grouped into four phases: most strings, strings beginning
+// with ":", strings beginning with "//", and strings beginning with "@". Within a phase
+// the strings are compared by their parts split at `.` and `:`, then by the number of
+// parts and finally by their values. Elements that aren't strings go before all strings
+// and are equal to each other, so the comparator is a total order.
+//
+// Buildifier never sorts a whole list with it: only runs of consecutive strings are sorted,
+// each run ends at an element that isn't a string or at a string preceded by a comment or
+// a blank line, and the elements keep their positions relative to the runs. Use
+// SortStringList to sort a list the same way.
+func CompareListElements(a, b Expr) int {
+	strA, okA := a.(*StringExpr)
+	strB, okB := b.(*StringExpr)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
 	}
-	return xi.original < xj.original
+	return compareSortKeys(makeSortKey(strA), makeSortKey(strB))
 }
 
 // fixMultilinePlus turns
@@ -19,7 +19,9 @@ distributed under the License is distributed on an "AS IS" BASIS,
 package build
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -110,6 +112,169 @@ func (f *File) DelRules(kind, name string) int {
 	return n
 }
 
+// RenameTarget renames the rule named oldName to newName and updates the references
+// to it from the rules of the same file, i.e. the labels ":oldName" and "//pkg:oldName",
+// where pkg is the package of the file. It returns the number of changed strings.
+func (f *File) RenameTarget(oldName, newName string) int {
+	pkg := filepath.ToSlash(filepath.Dir(f.Path))
+	if pkg == "." {
+		pkg = ""
+	}
+	labels := map[string]bool{
+		":" + oldName:              true,
+		"//" + pkg + ":" + oldName: true,
+	}
+
+	n := 0
+	for _, rule := range f.Rules("") {
+		if rule.Name() == oldName {
+			if str, ok := rule.Attr("name").(*StringExpr); ok {
+				str.Value = newName
+				n++
+			}
+		}
+		Walk(rule.Call, func(x Expr, stk []Expr) {
+			str, ok := x.(*StringExpr)
+			if !ok || !labels[str.Value] {
+				return
+			}
+			str.Value = strings.TrimSuffix(str.Value, oldName) + newName
+			n++
+		})
+	}
+	return n
+}
+
+// LoadInfo describes a single load statement of a file.
+type LoadInfo struct {
+	Module  string            // The label of the loaded file, e.g. "//foo:bar.bzl".
+	Symbols map[string]string // Local names of the loaded symbols mapped to their original names.
+	Load    *LoadStmt         // The underlying load statement.
+}
+
+// Loads returns the top-level load statements of the file in the order of their appearance.
+func (f *File) Loads() []*LoadInfo {
+	var loads []*LoadInfo
+	for _, stmt := range f.Stmt {
+		load, ok := stmt.(*LoadStmt)
+		if !ok {
+			continue
+		}
+		info := &LoadInfo{
+			Module:  load.Module.Value,
+			Symbols: make(map[string]string),
+			Load:    load,
+		}
+		for i, to := range load.To {
+			info.Symbols[to.Name] = load.From[i].Name
+		}
+		loads = append(loads, info)
+	}
+	return loads
+}
+
+// AddLoad makes the symbol loaded from the module available under the name alias
+// (or under its original name if alias is empty). If the file already loads from the
+// module, the symbol is appended to the last such load statement, otherwise a new load
+// statement is created after the leading comments and docstring of the file.
+// Nothing is changed if the symbol is already loaded under the same name.
+func (f *File) AddLoad(module, symbol, alias string) {
+	if alias == "" {
+		alias = symbol
+	}
+	f.Stmt = InsertLoad(f.Stmt, module, []string{symbol}, []string{alias})
+}
+
+func newLoad(location string, from, to []string) *LoadStmt {
+	load := &LoadStmt{
+		Module: &StringExpr{
+			Value: location,
+		},
+		ForceCompact: true,
+	}
+	for i := range from {
+		load.From = append(load.From, &Ident{Name: from[i]})
+		load.To = append(load.To, &Ident{Name: to[i]})
+	}
+	return load
+}
+
+// appendLoad tries to find an existing load location and append symbols to it.
+func appendLoad(stmts []Expr, location string, from, to []string) bool {
+	symbolsToLoad := make(map[string]string)
+	for i, s := range to {
+		symbolsToLoad[s] = from[i]
+	}
+	var lastLoad *LoadStmt
+	for _, s := range stmts {
+		load, ok := s.(*LoadStmt)
+		if !ok {
+			continue
+		}
+		if load.Module.Value != location {
+			continue // Loads a different file.
+		}
+		for _, ident := range load.To {
+			delete(symbolsToLoad, ident.Name) // Already loaded.
+		}
+		// Remember the last insert location, but potentially remove more symbols
+		// that are already loaded in other subsequent calls.
+		lastLoad = load
+	}
+
+	if lastLoad == nil {
+		return false
+	}
+
+	// Append the remaining loads to the last load location.
+	sortedSymbols := []string{}
+	for s := range symbolsToLoad {
+		sortedSymbols = append(sortedSymbols, s)
+	}
+	sort.Strings(sortedSymbols)
+	for _, s := range sortedSymbols {
+		lastLoad.From = append(lastLoad.From, &Ident{Name: symbolsToLoad[s]})
+		lastLoad.To = append(lastLoad.To, &Ident{Name: s})
+	}
+	return true
+}
+
+// InsertLoad inserts a load statement at the top of the list of statements.
+// The load statement is constructed using a string location and two slices of from- and to-symbols.
+// The function panics if the slices aren't of the same lentgh. Symbols that are already loaded
+// from the given filepath are ignored. If stmts already contains a load for the
+// location in arguments, appends the symbols to load to it.
+func InsertLoad(stmts []Expr, location string, from, to []string) []Expr {
+	if len(from) != len(to) {
+		panic(fmt.Errorf("length mismatch: %v (from) and %v (to)", len(from), len(to)))
+	}
+
+	if appendLoad(stmts, location, from, to) {
+		return stmts
+	}
+
+	load := newLoad(location, from, to)
+
+	var all []Expr
+	added := false
+	for i, stmt := range stmts {
+		_, isComment := stmt.(*CommentBlock)
+		_, isString := stmt.(*StringExpr)
+		isDocString := isString && i == 0
+		if isComment || isDocString || added {
+			all = append(all, stmt)
+			continue
+		}
+		all = append(all, load)
+		all = append(all, stmt)
+		added = true
+	}
+	if !added { // Empty file or just comments.
+		all = append(all, load)
+	}
+	return all
+}
+
 // If a build file contains exactly one unnamed rule, and no rules in the file explicitly have the
 // same name as the name of the directory the build file is in, we treat the unnamed rule as if it
 // had the name of the directory containing the BUILD file.
@@ -279,6 +444,42 @@ func (r *Rule) SetAttr(key string, val Expr) {
 	)
 }
 
+// Attr returns the value of the call's keyword argument with the given name.
+// If the call has no such argument, Attr returns nil.
+func (c *CallExpr) Attr(name string) Expr {
+	return (&Rule{Call: c}).Attr(name)
+}
+
+// SetAttr sets the call's keyword argument with the given name to value.
+// If the call has no such argument, SetAttr appends one to the end of
+// the argument list.
+func (c *CallExpr) SetAttr(name string, val Expr) {
+	(&Rule{Call: c}).SetAttr(name, val)
+}
+
+// Positional returns the positional arguments of the call, in order. Unpacked arguments
+// such as *args and **kwargs are included.
+func (c *CallExpr) Positional() []Expr {
+	var args []Expr
+	for _, arg := range c.List {
+		if _, ok := arg.(*AssignExpr); !ok {
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// Keyword returns the keyword arguments of the call, in order.
+func (c *CallExpr) Keyword() []*AssignExpr {
+	var args []*AssignExpr
+	for _, arg := range c.List {
+		if as, ok := arg.(*AssignExpr); ok {
+			args = append(args, as)
+		}
+	}
+	return args
+}
+
 // AttrLiteral returns the literal form of the rule's attribute
 // with the given key (such as "cc_api_version"), only when
 // that value is an identifier or number.
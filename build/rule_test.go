@@ -14,6 +14,7 @@ distributed under the License is distributed on an "AS IS" BASIS,
 package build
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -106,6 +107,32 @@ func TestSetKind(t *testing.T) {
 	})
 }
 
+func TestSetKindFormat(t *testing.T) {
+	f, err := ParseBzl("test.bzl", []byte(`def macro():
+    cc_library(name = "a")
+    native.cc_library(name = "b")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := f.Rules("")
+	rules[0].SetKind("cc_binary")
+	rules[1].SetKind("native.cc_binary")
+	for i, want := range []string{"cc_binary", "native.cc_binary"} {
+		if got := rules[i].Kind(); got != want {
+			t.Errorf("#%d: Kind() = %q, want %q", i, got, want)
+		}
+	}
+
+	want := `def macro():
+    cc_binary(name = "a")
+    native.cc_binary(name = "b")
+`
+	if got := string(Format(f)); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
 func TestRules(t *testing.T) {
 	f := &File{
 		Stmt: []Expr{
@@ -206,3 +233,234 @@ rule()`, "foo", `Use an implicit name for one unnamed rule with load`},
 		}
 	}
 }
+
+func TestCallExprAttr(t *testing.T) {
+	f, err := ParseBuild("BUILD", []byte(`cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := f.Stmt[0].(*CallExpr)
+
+	if srcs, ok := call.Attr("srcs").(*ListExpr); !ok || len(srcs.List) != 1 {
+		t.Errorf(`Attr("srcs") = %v, want ["foo.cc"]`, FormatString(call.Attr("srcs")))
+	}
+	if deps := call.Attr("deps"); deps != nil {
+		t.Errorf(`Attr("deps") = %v, want nil`, FormatString(deps))
+	}
+
+	call.SetAttr("name", NewString("bar"))
+	call.SetAttr("deps", NewList(NewString(":baz")))
+	if len(call.List) != 3 {
+		t.Errorf("got %d arguments, want 3", len(call.List))
+	}
+
+	want := `cc_library(
+    name = "bar",
+    srcs = ["foo.cc"],
+    deps = [":baz"],
+)
+`
+	if got := string(Format(f)); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCallExprArguments(t *testing.T) {
+	tests := []struct {
+		in         string
+		positional []string
+		keyword    []string
+	}{
+		{`foo(a, b = 1, c, *args, d = 2, **kwargs)`, []string{"a", "c", "*args", "**kwargs"}, []string{"b = 1", "d = 2"}},
+		{`foo(name = "x", srcs = [])`, nil, []string{`name = "x"`, "srcs = []"}},
+		{`foo()`, nil, nil},
+	}
+	for _, tt := range tests {
+		f, err := ParseBzl("test.bzl", []byte(tt.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		call := f.Stmt[0].(*CallExpr)
+
+		var positional, keyword []string
+		for _, arg := range call.Positional() {
+			positional = append(positional, FormatString(arg))
+		}
+		for _, arg := range call.Keyword() {
+			keyword = append(keyword, FormatString(arg))
+		}
+		if !reflect.DeepEqual(positional, tt.positional) {
+			t.Errorf("%s: Positional() = %q, want %q", tt.in, positional, tt.positional)
+		}
+		if !reflect.DeepEqual(keyword, tt.keyword) {
+			t.Errorf("%s: Keyword() = %q, want %q", tt.in, keyword, tt.keyword)
+		}
+	}
+}
+
+func TestLoads(t *testing.T) {
+	f, err := Parse("BUILD", []byte(`load("//foo:defs.bzl", "foo", my_bar = "bar")
+load(":local.bzl", "baz")
+
+foo(name = "x")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loads := f.Loads()
+	if len(loads) != 2 {
+		t.Fatalf("got %d loads, want 2", len(loads))
+	}
+	if loads[0].Module != "//foo:defs.bzl" {
+		t.Errorf("got module %q, want %q", loads[0].Module, "//foo:defs.bzl")
+	}
+	if want := map[string]string{"foo": "foo", "my_bar": "bar"}; !reflect.DeepEqual(loads[0].Symbols, want) {
+		t.Errorf("got symbols %v, want %v", loads[0].Symbols, want)
+	}
+	if want := map[string]string{"baz": "baz"}; !reflect.DeepEqual(loads[1].Symbols, want) {
+		t.Errorf("got symbols %v, want %v", loads[1].Symbols, want)
+	}
+}
+
+func TestAddLoad(t *testing.T) {
+	tests := []struct {
+		input  string
+		module string
+		symbol string
+		alias  string
+		output string
+	}{
+		{
+			input: `load("//foo:defs.bzl", "foo")
+
+foo(name = "x")
+`,
+			module: "//foo:defs.bzl",
+			symbol: "bar",
+			alias:  "my_bar",
+			output: `load("//foo:defs.bzl", "foo", my_bar = "bar")
+
+foo(name = "x")
+`,
+		},
+		{
+			input: `load("//foo:defs.bzl", "foo")
+`,
+			module: "//foo:defs.bzl",
+			symbol: "foo",
+			output: `load("//foo:defs.bzl", "foo")
+`,
+		},
+		{
+			input: `# Copyright
+
+foo(name = "x")
+`,
+			module: "//foo:defs.bzl",
+			symbol: "foo",
+			output: `# Copyright
+
+load("//foo:defs.bzl", "foo")
+
+foo(name = "x")
+`,
+		},
+	}
+
+	for i, tt := range tests {
+		f, err := Parse("BUILD", []byte(tt.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.AddLoad(tt.module, tt.symbol, tt.alias)
+		if got := string(Format(f)); got != tt.output {
+			t.Errorf("#%d: got\n%s\nwant\n%s", i, got, tt.output)
+		}
+	}
+}
+
+func TestRenameTarget(t *testing.T) {
+	f, err := Parse("foo/bar/BUILD", []byte(`cc_library(
+    name = "old",
+    srcs = ["old.cc"],
+)
+
+cc_binary(
+    name = "bin",
+    deps = [":old"],
+)
+
+cc_test(
+    name = "test",
+    deps = [
+        "//foo/bar:old",
+        "//other:old",
+    ],
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := f.RenameTarget("old", "new"); n != 3 {
+		t.Errorf("RenameTarget() = %d, want 3", n)
+	}
+	want := `cc_library(
+    name = "new",
+    srcs = ["old.cc"],
+)
+
+cc_binary(
+    name = "bin",
+    deps = [":new"],
+)
+
+cc_test(
+    name = "test",
+    deps = [
+        "//foo/bar:new",
+        "//other:old",
+    ],
+)
+`
+	if got := string(Format(f)); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAttrKeys(t *testing.T) {
+	f, err := Parse("BUILD", []byte(`cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+    deps = [":bar"],
+)
+
+macro(
+    "positional",
+    name = "baz",
+    *args,
+    visibility = ["//visibility:public"],
+    **kwargs
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rule *Rule
+		want []string
+	}{
+		{f.Rule(f.Stmt[0].(*CallExpr)), []string{"name", "srcs", "deps"}},
+		{f.Rule(f.Stmt[1].(*CallExpr)), []string{"name", "visibility"}},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.AttrKeys(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AttrKeys() of %q = %v, want %v", tt.rule.Kind(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// Resolution of the names used in a file.
+
+package build
+
+import (
+	"sort"
+
+	"github.com/bazelbuild/buildtools/tables"
+)
+
+// FreeVariables returns the sorted names of the variables that are used in the file
+// but not defined: they are neither assigned, loaded, defined as functions or parameters,
+// nor predeclared (see tables.StarlarkBuiltins). In .bzl files these are usually typos
+// or missing loads. In BUILD files the native rules are reported as well.
+func (f *File) FreeVariables() []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, ident := range f.FreeIdents() {
+		if !seen[ident.Name] {
+			seen[ident.Name] = true
+			names = append(names, ident.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FreeIdents is like FreeVariables but returns every use of the free variables,
+// in the order of their positions in the file.
+func (f *File) FreeIdents() []*Ident {
+	var free []*Ident
+	globals := newScope(nil)
+	globals.bindStatements(f.Stmt)
+	for _, stmt := range f.Stmt {
+		globals.resolve(stmt, &free)
+	}
+	sort.SliceStable(free, func(i, j int) bool {
+		return free[i].NamePos.Byte < free[j].NamePos.Byte
+	})
+	return free
+}
+
+// A scope is a set of names defined in a block of code: the file, a function or a comprehension.
+type scope struct {
+	parent *scope
+	names  map[string]bool
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, names: make(map[string]bool)}
+}
+
+// defines reports whether the name is defined in the scope or in any of the enclosing scopes.
+func (s *scope) defines(name string) bool {
+	for ; s != nil; s = s.parent {
+		if s.names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// bindStatements adds the names bound by the statements to the scope. A name bound anywhere
+// in a block is visible in the whole block, even before the binding statement.
+func (s *scope) bindStatements(stmts []Expr) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *AssignExpr:
+			s.bindTarget(stmt.LHS)
+		case *DefStmt:
+			s.names[stmt.Name] = true
+		case *LoadStmt:
+			for _, to := range stmt.To {
+				s.names[to.Name] = true
+			}
+		case *ForStmt:
+			s.bindTarget(stmt.Vars)
+			s.bindStatements(stmt.Body)
+		case *IfStmt:
+			s.bindStatements(stmt.True)
+			s.bindStatements(stmt.False)
+		}
+	}
+}
+
+// bindTarget adds the names assigned by an assignment target, such as `a` or `a, (b, c)`.
+func (s *scope) bindTarget(x Expr) {
+	switch x := x.(type) {
+	case *Ident:
+		s.names[x.Name] = true
+	case *ParenExpr:
+		s.bindTarget(x.X)
+	case *TupleExpr:
+		for _, item := range x.List {
+			s.bindTarget(item)
+		}
+	case *ListExpr:
+		for _, item := range x.List {
+			s.bindTarget(item)
+		}
+	}
+}
+
+// resolve adds to free the identifiers used in x that aren't defined in the scope.
+func (s *scope) resolve(x Expr, free *[]*Ident) {
+	switch x := x.(type) {
+	case *Ident:
+		if !s.defines(x.Name) && !tables.StarlarkBuiltins[x.Name] {
+			*free = append(*free, x)
+		}
+		return
+	case *LoadStmt:
+		// The loaded names are bindings, the original names belong to the loaded file.
+		return
+	case *CallExpr:
+		s.resolve(x.X, free)
+		for _, arg := range x.List {
+			// The names of keyword arguments aren't variables.
+			if as, ok := arg.(*AssignExpr); ok {
+				s.resolve(as.RHS, free)
+				continue
+			}
+			s.resolve(arg, free)
+		}
+		return
+	case *DefStmt:
+		s.resolveFunction(&x.Function, free)
+		return
+	case *LambdaExpr:
+		s.resolveFunction(&x.Function, free)
+		return
+	case *Comprehension:
+		// The first iterable is evaluated in the enclosing scope,
+		// everything else in the scope of the comprehension.
+		inner := newScope(s)
+		for _, clause := range x.Clauses {
+			if clause, ok := clause.(*ForClause); ok {
+				inner.bindTarget(clause.Vars)
+			}
+		}
+		for i, clause := range x.Clauses {
+			if clause, ok := clause.(*ForClause); ok && i == 0 {
+				s.resolve(clause.X, free)
+				continue
+			}
+			inner.resolve(clause, free)
+		}
+		inner.resolve(x.Body, free)
+		return
+	}
+	WalkOnce(x, func(child *Expr) {
+		s.resolve(*child, free)
+	})
+}
+
+// resolveFunction adds to free the identifiers used in a function or a lambda that aren't
+// defined in the function or in the scope.
+func (s *scope) resolveFunction(fn *Function, free *[]*Ident) {
+	inner := newScope(s)
+	for _, param := range fn.Params {
+		switch param := param.(type) {
+		case *Ident:
+			inner.bindTarget(param)
+		case *AssignExpr:
+			// Default values are evaluated in the enclosing scope.
+			s.resolve(param.RHS, free)
+			inner.bindTarget(param.LHS)
+		case *UnaryExpr:
+			// *args and **kwargs, a bare * has no name.
+			if param.X != nil {
+				inner.bindTarget(param.X)
+			}
+		}
+	}
+	inner.bindStatements(fn.Body)
+	for _, stmt := range fn.Body {
+		inner.resolve(stmt, free)
+	}
+}
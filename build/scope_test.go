@@ -0,0 +1,88 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package build
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFreeVariables(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{
+			in: `load(":defs.bzl", "helper", alias = "original")
+
+CONSTANT = [x * 2 for x in range(10) if x != SKIPPED]
+
+def _impl(ctx, *args, prefix = DEFAULT_PREFIX, **kwargs):
+    result = helper(ctx.attr.name, alias)
+    for i, (key, value) in enumerate(kwargs.items()):
+        result += str(i) + key + value
+    callback = lambda y: y + missing
+    return [DefaultInfo(files = depset(ctx.files.srcs))] + LATER
+
+my_rule = rule(implementation = _impl, attrs = {"srcs": attr.label_list()})
+
+LATER = []
+`,
+			want: []string{"DEFAULT_PREFIX", "SKIPPED", "missing"},
+		},
+		{
+			in: `load(":defs.bzl", "helper")
+
+def _impl(ctx):
+    files = [f for f in ctx.files.srcs if f.extension == "cc"]
+    return [DefaultInfo(files = depset(files)), helper(ctx)]
+
+my_rule = rule(implementation = _impl)
+`,
+			want: []string{},
+		},
+	}
+	for i, tt := range tests {
+		f, err := ParseBzl("test.bzl", []byte(tt.in))
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := f.FreeVariables(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("#%d: FreeVariables() = %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestFreeIdents(t *testing.T) {
+	f, err := ParseBzl("test.bzl", []byte(`def f(x):
+    return foo(x, bar) + foo
+
+foo(name = baz)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ident := range f.FreeIdents() {
+		got = append(got, fmt.Sprintf("%d:%s", ident.NamePos.Line, ident.Name))
+	}
+	want := []string{"2:foo", "2:bar", "2:foo", "4:foo", "4:baz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FreeIdents() = %q, want %q", got, want)
+	}
+}
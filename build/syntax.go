@@ -20,6 +20,7 @@ package build
 // Syntax data structure definitions.
 
 import (
+	"fmt"
 	"strings"
 	"unicode/utf8"
 )
@@ -31,6 +32,11 @@ type Position struct {
 	Byte     int // byte in input (starting at 0)
 }
 
+// String returns the position formatted as "line:col", e.g. "12:5".
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.LineRune)
+}
+
 // add returns the position at the end of s, assuming it starts at p.
 func (p Position) add(s string) Position {
 	p.Byte += len(s)
@@ -79,12 +85,70 @@ func (c *Comments) Comment() *Comments {
 	return c
 }
 
+// MoveComments moves all comments attached to the node from to the node to,
+// appending them to the comments that are already attached to it.
+func MoveComments(from, to Expr) {
+	src, dst := from.Comment(), to.Comment()
+	if src == dst {
+		return
+	}
+	dst.Before = append(dst.Before, src.Before...)
+	dst.Suffix = append(dst.Suffix, src.Suffix...)
+	dst.After = append(dst.After, src.After...)
+	ClearComments(from)
+}
+
+// ClearComments removes all comments attached to the node e.
+func ClearComments(e Expr) {
+	*e.Comment() = Comments{}
+}
+
 // A File represents an entire BUILD file.
 type File struct {
 	Path string // file path, relative to workspace directory
 	Type FileType
 	Comments
 	Stmt []Expr
+
+	annotations    map[Expr]map[string]interface{} // see SetAnnotation
+	trailingCommas map[Position]bool               // see HasTrailingComma
+}
+
+// SetAnnotation attaches arbitrary metadata to a node of the file, e.g. to pass
+// information between several passes of an analysis. Annotations are ignored
+// by the printer and are not preserved by Copy.
+func (f *File) SetAnnotation(node Expr, key string, val interface{}) {
+	if f.annotations == nil {
+		f.annotations = make(map[Expr]map[string]interface{})
+	}
+	if f.annotations[node] == nil {
+		f.annotations[node] = make(map[string]interface{})
+	}
+	f.annotations[node][key] = val
+}
+
+// GetAnnotation returns the metadata attached to the node by SetAnnotation
+// and reports whether it has been found.
+func (f *File) GetAnnotation(node Expr, key string) (interface{}, bool) {
+	val, ok := f.annotations[node][key]
+	return val, ok
+}
+
+// HasTrailingComma reports whether the last element of the list, dict or call x
+// is followed by a comma in the source of the file. It's always false for nodes
+// that haven't been produced by the parser.
+func (f *File) HasTrailingComma(x Expr) bool {
+	switch x := x.(type) {
+	case *ListExpr:
+		return f.trailingCommas[x.End.Pos]
+	case *DictExpr:
+		return f.trailingCommas[x.End.Pos]
+	case *CallExpr:
+		return f.trailingCommas[x.End.Pos]
+	case *TupleExpr:
+		return f.trailingCommas[x.End.Pos]
+	}
+	return false
 }
 
 // DisplayPath returns the filename if it's not empty, "<stdin>" otherwise
@@ -95,6 +159,11 @@ func (f *File) DisplayPath() string {
 	return f.Path
 }
 
+// PosString returns a position in the file formatted as "path:line:col", e.g. "pkg/BUILD:12:5".
+func (f *File) PosString(p Position) string {
+	return f.DisplayPath() + ":" + p.String()
+}
+
 func (f *File) Span() (start, end Position) {
 	if len(f.Stmt) == 0 {
 		p := Position{Line: 1, LineRune: 1}
@@ -127,6 +196,11 @@ func (x *Ident) Span() (start, end Position) {
 	return x.NamePos, x.NamePos.add(x.Name)
 }
 
+// NewIdent returns a new identifier with the given name.
+func NewIdent(name string) *Ident {
+	return &Ident{Name: name}
+}
+
 // BranchStmt represents a `pass`, `break`, or `continue` statement.
 type BranchStmt struct {
 	Comments
@@ -178,6 +252,11 @@ func (x *StringExpr) Span() (start, end Position) {
 	return x.Start, x.End
 }
 
+// NewString returns a new string literal with the given value, it's printed with double quotes.
+func NewString(s string) *StringExpr {
+	return &StringExpr{Value: s}
+}
+
 // An End represents the end of a parenthesized or bracketed expression.
 // It is a place to hang comments.
 type End struct {
@@ -287,6 +366,15 @@ func (x *DictExpr) Span() (start, end Position) {
 	return x.Start, x.End.Pos.add("}")
 }
 
+// NewDict returns a new dict literal with the given key-value pairs.
+func NewDict(pairs ...[2]Expr) *DictExpr {
+	list := []Expr{}
+	for _, pair := range pairs {
+		list = append(list, &KeyValueExpr{Key: pair[0], Value: pair[1]})
+	}
+	return &DictExpr{List: list}
+}
+
 // A ListExpr represents a list literal: [ List ].
 type ListExpr struct {
 	Comments
@@ -300,6 +388,11 @@ func (x *ListExpr) Span() (start, end Position) {
 	return x.Start, x.End.Pos.add("]")
 }
 
+// NewList returns a new list literal with the given items.
+func NewList(items ...Expr) *ListExpr {
+	return &ListExpr{List: append([]Expr{}, items...)}
+}
+
 // A SetExpr represents a set literal: { List }.
 type SetExpr struct {
 	Comments
@@ -520,6 +613,23 @@ func (x *ReturnStmt) Span() (start, end Position) {
 	return x.Return, end
 }
 
+// An AssertStmt represents an assert statement: assert x == 1, "message".
+type AssertStmt struct {
+	Comments
+	Assert  Position
+	Cond    Expr
+	Message Expr // may be nil
+}
+
+func (x *AssertStmt) Span() (start, end Position) {
+	if x.Message == nil {
+		_, end = x.Cond.Span()
+	} else {
+		_, end = x.Message.Span()
+	}
+	return x.Assert, end
+}
+
 // A ForStmt represents a for loop block: for x in range(10):.
 type ForStmt struct {
 	Comments
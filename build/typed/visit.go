@@ -0,0 +1,41 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package typed provides helpers for the syntax trees of the build package that use type
+// parameters. It's separate from the build package because it requires Go 1.18, which is
+// newer than the toolchain the repository is built with.
+package typed
+
+import "github.com/bazelbuild/buildtools/build"
+
+// Visit walks the syntax tree of the file in a preorder traversal and calls fn on each node
+// of the type T, e.g.
+//
+//	typed.Visit(f, func(call *build.CallExpr) { ... })
+//
+// Code that has to build with older toolchains should use build.Walk with a type assertion
+// instead.
+func Visit[T build.Expr](f *build.File, fn func(node T)) {
+	build.Walk(f, func(x build.Expr, stk []build.Expr) {
+		switch node := x.(type) {
+		case T:
+			fn(node)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package typed
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bazelbuild/buildtools/build"
+)
+
+func TestVisit(t *testing.T) {
+	f, err := build.ParseBuild("BUILD", []byte(`load(":foo.bzl", "foo")
+
+foo(
+    name = "a",
+    srcs = glob(["*.cc"]) + ["b.cc"],
+)
+
+x = 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values []string
+	Visit(f, func(str *build.StringExpr) {
+		values = append(values, str.Value)
+	})
+	if want := []string{":foo.bzl", "a", "*.cc", "b.cc"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Visit(*build.StringExpr) = %q, want %q", values, want)
+	}
+
+	var calls []string
+	Visit(f, func(call *build.CallExpr) {
+		calls = append(calls, build.FormatString(call.X))
+	})
+	if want := []string{"foo", "glob"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Visit(*build.CallExpr) = %q, want %q", calls, want)
+	}
+}
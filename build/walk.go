@@ -22,6 +22,10 @@ package build
 // The stk argument is the stack of expressions in the recursion above x,
 // from outermost to innermost.
 //
+// Only the subtree rooted at v is visited, so v doesn't have to be a file:
+// walking a single rule's *CallExpr visits its arguments but no sibling
+// statements, and the stack starts at v rather than at the file.
+//
 func Walk(v Expr, f func(x Expr, stk []Expr)) {
 	var stack []Expr
 	walk1(&v, &stack, func(x *Expr, stk []Expr) Expr {
@@ -198,6 +202,11 @@ func WalkOnce(v Expr, f func(x *Expr)) {
 		if v.Result != nil {
 			f(&v.Result)
 		}
+	case *AssertStmt:
+		f(&v.Cond)
+		if v.Message != nil {
+			f(&v.Message)
+		}
 	}
 }
 
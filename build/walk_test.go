@@ -38,6 +38,40 @@ func TestWalk(t *testing.T) {
 	compare(t, prefix, []string{"*", "+", "1", "2", "-", "3", "4"})
 }
 
+func TestWalkSubtree(t *testing.T) {
+	f, err := ParseBuild("BUILD", []byte(`foo(name = "a")
+
+bar(
+    name = "b",
+    srcs = ["b.cc"],
+)
+
+baz(name = "c")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	var depths []int
+	Walk(f.Stmt[1], func(e Expr, stk []Expr) {
+		visited = append(visited, FormatString(e))
+		depths = append(depths, len(stk))
+	})
+	compare(t, visited, []string{
+		"bar(\n    name = \"b\",\n    srcs = [\"b.cc\"],\n)",
+		"bar",
+		"name = \"b\"",
+		"name",
+		"\"b\"",
+		"srcs = [\"b.cc\"]",
+		"srcs",
+		"[\"b.cc\"]",
+		"\"b.cc\"",
+	})
+	compare(t, depths, []int{0, 1, 1, 2, 2, 1, 2, 2, 3})
+}
+
 func TestWalkOnce(t *testing.T) {
 	var prefix []string
 	var postfix []string
@@ -21,10 +21,13 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/bazelbuild/buildtools/build"
 	"github.com/bazelbuild/buildtools/buildifier/utils"
@@ -37,21 +40,24 @@ var buildVersion = "redacted"
 var buildScmRevision = "redacted"
 
 var (
-	help          = flag.Bool("help", false, "print usage information")
-	vflag         = flag.Bool("v", false, "print verbose information to standard error")
-	dflag         = flag.Bool("d", false, "alias for -mode=diff")
-	rflag         = flag.Bool("r", false, "find starlark files recursively")
-	mode          = flag.String("mode", "", "formatting mode: check, diff, or fix (default fix)")
-	format        = flag.String("format", "", "diagnostics format: text or json (default text)")
-	diffProgram   = flag.String("diff_command", "", "command to run when the formatting mode is diff (default uses the BUILDIFIER_DIFF, BUILDIFIER_MULTIDIFF, and DISPLAY environment variables to create the diff command)")
-	multiDiff     = flag.Bool("multi_diff", false, "the command specified by the -diff_command flag can diff multiple files in the style of tkdiff (default false)")
-	lint          = flag.String("lint", "", "lint mode: off, warn, or fix (default off)")
-	warnings      = flag.String("warnings", "", "comma-separated warnings used in the lint mode or \"all\"")
-	filePath      = flag.String("path", "", "assume BUILD file has this path relative to the workspace directory")
-	tablesPath    = flag.String("tables", "", "path to JSON file with custom table definitions which will replace the built-in tables")
-	addTablesPath = flag.String("add_tables", "", "path to JSON file with custom table definitions which will be merged with the built-in tables")
-	version       = flag.Bool("version", false, "Print the version of buildifier")
-	inputType     = flag.String("type", "auto", "Input file type: build (for BUILD files), bzl (for .bzl files), workspace (for WORKSPACE files), default (for generic Starlark files) or auto (default, based on the filename)")
+	help           = flag.Bool("help", false, "print usage information")
+	vflag          = flag.Bool("v", false, "print verbose information to standard error")
+	dflag          = flag.Bool("d", false, "alias for -mode=diff")
+	rflag          = flag.Bool("r", false, "find starlark files recursively")
+	followSymlinks = flag.Bool("follow_symlinks", false, "follow symlinks to directories when finding starlark files recursively with -r")
+	mode           = flag.String("mode", "", "formatting mode: check, diff, fix, print_if_changed, or validate (default fix)")
+	format         = flag.String("format", "", "diagnostics format: text, json, or sarif (default text)")
+	outputManifest = flag.String("output_manifest", "", "file to write the names of the files that need reformatting to, one per line (only with -mode=check)")
+	diffProgram    = flag.String("diff_command", "", "command to run when the formatting mode is diff (default uses the BUILDIFIER_DIFF, BUILDIFIER_MULTIDIFF, and DISPLAY environment variables to create the diff command)")
+	multiDiff      = flag.Bool("multi_diff", false, "the command specified by the -diff_command flag can diff multiple files in the style of tkdiff (default false)")
+	lint           = flag.String("lint", "", "lint mode: off, warn, or fix (default off)")
+	warnings       = flag.String("warnings", "", "comma-separated warnings used in the lint mode or \"all\"")
+	filePath       = flag.String("path", "", "assume BUILD file has this path relative to the workspace directory (also used to detect the type of the input read from stdin)")
+	tablesPath     = flag.String("tables", "", "path to JSON file with custom table definitions which will replace the built-in tables")
+	addTablesPath  = flag.String("add_tables", "", "path to JSON file with custom table definitions which will be merged with the built-in tables")
+	version        = flag.Bool("version", false, "Print the version of buildifier")
+	parallel       = flag.Int("parallel", 1, "number of files to process concurrently, the output doesn't depend on it (files are always processed one by one in diff mode)")
+	inputType      = flag.String("type", "auto", "Input file type: build (for BUILD files), bzl (for .bzl files), workspace (for WORKSPACE files), default (for generic Starlark files) or auto (default, based on the filename)")
 
 	// Debug flags passed through to rewrite.go
 	allowSort = stringList("allowsort", "additional sort contexts to treat as safe")
@@ -69,16 +75,18 @@ func usage() {
 	fmt.Fprintf(flag.CommandLine.Output(), `usage: buildifier [-d] [-v] [-r] [-diff_command=command] [-help] [-multi_diff] [-mode=mode] [-lint=lint_mode] [-path=path] [files...]
 
 Buildifier applies standard formatting to the named Starlark files.  The mode
-flag selects the processing: check, diff, fix, or print_if_changed.  In check
-mode, buildifier prints a list of files that need reformatting.  In diff mode,
+flag selects the processing: check, diff, fix, print_if_changed, or validate.  In check
+mode, buildifier prints a list of files that need reformatting, the -output_manifest
+flag also writes their names to a file, one per line.  In diff mode,
 buildifier shows the diffs that it would make.  It creates the diffs by running
 a diff command, which can be specified using the -diff_command flag. You can
 indicate that the diff command can show differences between more than two files
 in the manner of tkdiff by specifying the -multi_diff flag.  In fix mode,
 buildifier updates the files that need reformatting and, if the -v flag is
 given, prints their names to standard error.  In print_if_changed mode,
-buildifier shows the file contents it would write.  The default mode is fix. -d
-is an alias for -mode=diff.
+buildifier shows the file contents it would write.  In validate mode, buildifier
+only checks that the files can be parsed, without formatting them.  The default
+mode is fix. -d is an alias for -mode=diff.
 
 The lint flag selects the lint mode to be used: off, warn, fix.
 In off mode, the linting is not performed.
@@ -153,6 +161,16 @@ func main() {
 		os.Exit(2)
 	}
 
+	if *outputManifest != "" && *mode != "check" {
+		fmt.Fprintf(os.Stderr, "buildifier: -output_manifest can only be used with -mode=check\n")
+		os.Exit(2)
+	}
+
+	if *parallel < 1 {
+		fmt.Fprintf(os.Stderr, "buildifier: -parallel must be positive, got %d\n", *parallel)
+		os.Exit(2)
+	}
+
 	// If the path flag is set, must only be formatting a single file.
 	// It doesn't make sense for multiple files to have the same path.
 	if (*filePath != "" || *mode == "print_if_changed") && len(args) > 1 {
@@ -206,13 +224,13 @@ func run(args, warningsList *[]string) int {
 			*mode = "pipe"
 		}
 		var fileDiagnostics *utils.FileDiagnostics
-		fileDiagnostics, exitCode = processFile("", data, *inputType, *lint, warningsList, false, tf)
+		fileDiagnostics, exitCode = processFile("", data, *inputType, *lint, warningsList, false, tf, os.Stderr)
 		diagnostics = utils.NewDiagnostics(fileDiagnostics)
 	} else {
 		files := *args
 		if *rflag {
 			var err error
-			files, err = utils.ExpandDirectories(args)
+			files, err = utils.ExpandDirectories(args, *followSymlinks)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "buildifier: %v\n", err)
 				return 3
@@ -221,7 +239,22 @@ func run(args, warningsList *[]string) int {
 		diagnostics, exitCode = processFiles(files, *inputType, *lint, warningsList, tf)
 	}
 
+	if *outputManifest != "" {
+		var manifest strings.Builder
+		for _, file := range diagnostics.Unformatted() {
+			manifest.WriteString(file + "\n")
+		}
+		if err := ioutil.WriteFile(*outputManifest, []byte(manifest.String()), 0666); err != nil {
+			fmt.Fprintf(os.Stderr, "buildifier: %v\n", err)
+			return 3
+		}
+	}
+
 	diagnosticsOutput := diagnostics.Format(*format, *vflag)
+	if *mode == "validate" {
+		// Syntax errors have already been reported, the files aren't checked for formatting.
+		diagnosticsOutput = ""
+	}
 	if *format != "" {
 		// Explicitly provided --format means the diagnostics are printed to stdout
 		fmt.Printf(diagnosticsOutput)
@@ -247,7 +280,12 @@ func processFiles(files []string, inputType, lint string, warningsList *[]string
 	if n := (len(files) + 9) / 10; nworker > n {
 		nworker = n
 	}
-	runtime.GOMAXPROCS(nworker + 1)
+	// Files are processed by nprocess goroutines, the differ can't be used concurrently.
+	nprocess := *parallel
+	if *mode == "diff" {
+		nprocess = 1
+	}
+	runtime.GOMAXPROCS(nworker + nprocess)
 
 	// Start nworker workers reading stripes of the input
 	// argument list and sending the resulting data on
@@ -271,11 +309,20 @@ func processFiles(files []string, inputType, lint string, warningsList *[]string
 		}(i)
 	}
 
-	exitCode := 0
-	fileDiagnostics := []*utils.FileDiagnostics{}
+	// The results of concurrently processed files, including the messages to the
+	// standard error, are collected and reported in the order of the input files
+	// to keep the output deterministic.
+	type processed struct {
+		fd       *utils.FileDiagnostics
+		exitCode int
+		stderr   bytes.Buffer
+	}
+	results := make([]processed, len(files))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, nprocess)
 
-	// Process files. The processing still runs in a single goroutine
-	// in sequence. Only the reading of the files has been parallelized.
+	// Process files. By default the processing runs in a single goroutine
+	// in sequence, only the reading of the files has been parallelized.
 	// The goal is to optimize for runs where most files are already
 	// formatted correctly, so that reading is the bulk of the I/O.
 	for i, file := range files {
@@ -284,17 +331,40 @@ func processFiles(files []string, inputType, lint string, warningsList *[]string
 			fmt.Fprintf(os.Stderr, "buildifier: internal phase error: got %s for %s", res.file, file)
 			os.Exit(3)
 		}
+		var stderr io.Writer = os.Stderr
+		if nprocess > 1 {
+			stderr = &results[i].stderr
+		}
 		if res.err != nil {
-			fmt.Fprintf(os.Stderr, "buildifier: %v\n", res.err)
-			exitCode = 3
+			fmt.Fprintf(stderr, "buildifier: %v\n", res.err)
+			results[i].exitCode = 3
 			continue
 		}
-		fd, newExitCode := processFile(file, res.data, inputType, lint, warningsList, len(files) > 1, tf)
-		if fd != nil {
-			fileDiagnostics = append(fileDiagnostics, fd)
+		if nprocess == 1 {
+			results[i].fd, results[i].exitCode = processFile(file, res.data, inputType, lint, warningsList, len(files) > 1, tf, stderr)
+			continue
 		}
-		if newExitCode != 0 {
-			exitCode = newExitCode
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, file string, data []byte, stderr io.Writer) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i].fd, results[i].exitCode = processFile(file, data, inputType, lint, warningsList, len(files) > 1, tf, stderr)
+		}(i, file, res.data, stderr)
+	}
+	wg.Wait()
+
+	exitCode := 0
+	fileDiagnostics := []*utils.FileDiagnostics{}
+	for i := range results {
+		os.Stderr.Write(results[i].stderr.Bytes())
+		if results[i].fd != nil {
+			fileDiagnostics = append(fileDiagnostics, results[i].fd)
+		}
+		if results[i].exitCode != 0 {
+			exitCode = results[i].exitCode
 		}
 	}
 	return utils.NewDiagnostics(fileDiagnostics...), exitCode
@@ -305,25 +375,40 @@ var diff *differ.Differ
 
 // processFile processes a single file containing data.
 // It has been read from filename and should be written back if fixing.
-func processFile(filename string, data []byte, inputType, lint string, warningsList *[]string, displayFileNames bool, tf *utils.TempFile) (*utils.FileDiagnostics, int) {
+// Messages are reported to stderr.
+func processFile(filename string, data []byte, inputType, lint string, warningsList *[]string, displayFileNames bool, tf *utils.TempFile, stderr io.Writer) (*utils.FileDiagnostics, int) {
 	var exitCode int
 
 	parser := utils.GetParser(inputType)
 
-	f, err := parser(filename, data)
+	// The input read from stdin has no name, use the one passed with -path to detect its type.
+	parseName := filename
+	if filename == "" && inputType == "auto" {
+		parseName = *filePath
+	}
+	f, err := parser(parseName, data)
 	if err != nil {
 		// Do not use buildifier: prefix on this error.
 		// Since it is a parse error, it begins with file:line:
 		// and we want that to be the first thing in the error.
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(stderr, "%v\n", err)
 		if exitCode < 1 {
 			exitCode = 1
 		}
 		return utils.InvalidFileDiagnostics(filename), exitCode
 	}
+	f.Path = filename
 
-	pkg := utils.GetPackageName(filename)
-	warnings := utils.Lint(f, pkg, lint, warningsList, *vflag)
+	if *mode == "validate" {
+		// validate mode: the file has been parsed successfully, nothing else to check.
+		return utils.NewFileDiagnostics(f.DisplayPath(), nil), exitCode
+	}
+
+	ctx := &warn.WarningContext{Pkg: utils.GetPackageName(filename)}
+	if filename != "" {
+		ctx.Dir = filepath.Dir(filename)
+	}
+	warnings := utils.LintWithOutput(f, ctx, lint, warningsList, *vflag, stderr)
 	if len(warnings) > 0 {
 		exitCode = 4
 	}
@@ -337,23 +422,24 @@ func processFile(filename string, data []byte, inputType, lint string, warningsL
 
 	ndata := build.Format(f)
 
+	// Files that are already formatted are never written or diffed, only the
+	// pipe mode needs the output regardless of whether it has changed.
+	if *mode != "pipe" && bytes.Equal(data, ndata) {
+		return fileDiagnostics, exitCode
+	}
+
 	switch *mode {
 	case "check":
 		// check mode: print names of files that need formatting.
-		if !bytes.Equal(data, ndata) {
-			fileDiagnostics.Formatted = false
-			fileDiagnostics.SetRewrites(info.Stats())
-			return fileDiagnostics, 4
-		}
+		fileDiagnostics.Formatted = false
+		fileDiagnostics.SetRewrites(info.Stats())
+		return fileDiagnostics, 4
 
 	case "diff":
 		// diff mode: run diff on old and new.
-		if bytes.Equal(data, ndata) {
-			return fileDiagnostics, exitCode
-		}
 		outfile, err := tf.WriteTemp(ndata)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "buildifier: %v\n", err)
+			fmt.Fprintf(stderr, "buildifier: %v\n", err)
 			return fileDiagnostics, 3
 		}
 		infile := filename
@@ -362,15 +448,15 @@ func processFile(filename string, data []byte, inputType, lint string, warningsL
 			// Write it to a temporary file so diff can read it.
 			infile, err = tf.WriteTemp(data)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "buildifier: %v\n", err)
+				fmt.Fprintf(stderr, "buildifier: %v\n", err)
 				return fileDiagnostics, 3
 			}
 		}
 		if displayFileNames {
-			fmt.Fprintf(os.Stderr, "%v:\n", f.DisplayPath())
+			fmt.Fprintf(stderr, "%v:\n", f.DisplayPath())
 		}
 		if err := diff.Show(infile, outfile); err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
+			fmt.Fprintf(stderr, "%v\n", err)
 			return fileDiagnostics, 4
 		}
 
@@ -381,26 +467,18 @@ func processFile(filename string, data []byte, inputType, lint string, warningsL
 
 	case "fix":
 		// fix mode: update files in place as needed.
-		if bytes.Equal(data, ndata) {
-			return fileDiagnostics, exitCode
-		}
-
 		err := ioutil.WriteFile(filename, ndata, 0666)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "buildifier: %s\n", err)
+			fmt.Fprintf(stderr, "buildifier: %s\n", err)
 			return fileDiagnostics, 3
 		}
 
 		if *vflag {
-			fmt.Fprintf(os.Stderr, "fixed %s\n", f.DisplayPath())
+			fmt.Fprintf(stderr, "fixed %s\n", f.DisplayPath())
 		}
 	case "print_if_changed":
-		if bytes.Equal(data, ndata) {
-			return fileDiagnostics, exitCode
-		}
-
 		if _, err := os.Stdout.Write(ndata); err != nil {
-			fmt.Fprintf(os.Stderr, "buildifier: error writing output: %v\n", err)
+			fmt.Fprintf(stderr, "buildifier: error writing output: %v\n", err)
 			return fileDiagnostics, 3
 		}
 	}
@@ -82,12 +82,82 @@ diff stdout golden/test.bzl.golden
 diff test_dir/test.bzl.out golden/test.bzl.golden
 diff test_dir/.git/git.bzl golden/git.bzl
 
+# The type of the input read from stdin can be set explicitly or detected from --path
+echo -e "$INPUT" | "$buildifier" --type=build > stdout_type
+diff stdout_type golden/BUILD.golden || die "stdin: wrong output with --type=build"
+echo -e "$INPUT" | "$buildifier" --path=pkg/BUILD > stdout_path
+diff stdout_path golden/BUILD.golden || die "stdin: wrong output with --path=pkg/BUILD"
+echo -e "$INPUT" | "$buildifier" --type=bzl --path=pkg/BUILD > stdout_type_path
+diff stdout_type_path golden/test.bzl.golden || die "stdin: wrong output with --type=bzl --path=pkg/BUILD"
+
+# Test the validate mode, it reports syntax errors without formatting the files
+cp test_dir/build test_validate.bzl
+echo -e "$INPUT" > test_validate_build
+echo -e "foo(\nbar = [1,,2]\n)" > test_validate_invalid.bzl
+"$buildifier" --mode=validate test_validate.bzl test_validate_build 2> validate_error || die "validate: expected valid files to pass"
+[[ -s validate_error ]] && die "validate: unexpected output for valid files: $(cat validate_error)"
+diff test_validate_build <(echo -e "$INPUT") || die "validate: the file shouldn't be modified"
+ret=0
+"$buildifier" --mode=validate test_validate.bzl test_validate_invalid.bzl 2> validate_error || ret=$?
+if [[ $ret -ne 1 ]]; then
+  die "validate: expected buildifier to exit with 1, actual: $ret"
+fi
+echo "test_validate_invalid.bzl:2:11: syntax error near ," > golden/validate_error_golden
+diff validate_error golden/validate_error_golden || die "validate: wrong error message"
+
+# Test that --output_manifest lists the files that would be reformatted
+mkdir -p test_manifest
+cp test_dir/test.bzl test_manifest/clean.bzl
+echo -e "$INPUT" > test_manifest/dirty.bzl
+echo -e "$INPUT" > test_manifest/BUILD
+cp test_manifest/dirty.bzl test_manifest/dirty.bzl.orig
+ret=0
+"$buildifier" --mode=check --output_manifest=manifest test_manifest/BUILD test_manifest/clean.bzl test_manifest/dirty.bzl 2> /dev/null || ret=$?
+if [[ $ret -ne 4 ]]; then
+  die "output_manifest: expected buildifier to exit with 4, actual: $ret"
+fi
+cat > golden/manifest_golden <<EOF
+test_manifest/BUILD
+test_manifest/dirty.bzl
+EOF
+diff manifest golden/manifest_golden || die "output_manifest: wrong manifest contents"
+diff test_manifest/dirty.bzl test_manifest/dirty.bzl.orig || die "output_manifest: the file shouldn't be modified"
+
+# Test that files that are already formatted aren't rewritten
+cp test_dir/test.bzl test_unchanged.bzl
+touch -t 200001010000 test_unchanged.bzl unchanged_mtime
+"$buildifier" -v test_unchanged.bzl 2> unchanged_error || die "unchanged: expected buildifier to succeed"
+[[ -s unchanged_error ]] && die "unchanged: unexpected output: $(cat unchanged_error)"
+[[ -z "$(find test_unchanged.bzl -newer unchanged_mtime)" ]] || die "unchanged: the file shouldn't be rewritten"
+
 # Test run on a directory without -r
 "$buildifier" test_dir || ret=$?
 if [[ $ret -ne 3 ]]; then
   die "Directory without -r: expected buildifier to exit with 3, actual: $ret"
 fi
 
+# Test that processing files in parallel gives the same results as processing them serially
+mkdir -p test_parallel/serial
+for i in $(seq 1 30); do
+  echo -e "$INPUT" > "test_parallel/serial/test_$i.bzl"
+  echo -e "$INPUT" > "test_parallel/serial/BUILD.$i"
+done
+echo -e "not valid (" > test_parallel/serial/invalid.bzl
+cp -r test_parallel/serial test_parallel/parallel
+
+ret_serial=0
+ret_parallel=0
+"$buildifier" --mode=check --lint=warn -r test_parallel/serial > test_parallel/out_serial 2> test_parallel/err_serial || ret_serial=$?
+"$buildifier" --mode=check --lint=warn -r --parallel=4 test_parallel/parallel > test_parallel/out_parallel 2> test_parallel/err_parallel || ret_parallel=$?
+[[ $ret_serial -eq $ret_parallel ]] || die "--parallel: expected exit code $ret_serial, actual: $ret_parallel"
+sed 's#test_parallel/parallel/#test_parallel/serial/#g' test_parallel/err_parallel | diff test_parallel/err_serial - || die "--parallel: wrong console output in check mode"
+diff test_parallel/out_serial test_parallel/out_parallel || die "--parallel: wrong standard output in check mode"
+
+"$buildifier" --lint=fix -v -r test_parallel/serial 2> test_parallel/err_serial || true
+"$buildifier" --lint=fix -v -r --parallel=4 test_parallel/parallel 2> test_parallel/err_parallel || true
+sed 's#test_parallel/parallel/#test_parallel/serial/#g' test_parallel/err_parallel | diff test_parallel/err_serial - || die "--parallel: wrong console output in fix mode"
+diff -r test_parallel/serial test_parallel/parallel || die "--parallel: files are fixed differently"
+
 # Test the linter
 
 cat > test_dir/to_fix.bzl <<EOF
@@ -125,10 +195,10 @@ test_dir/to_fix_tmp.bzl: applied fixes, 1 warnings left
 fixed test_dir/to_fix_tmp.bzl
 EOF
 
-error_docstring="test_dir/to_fix_tmp.bzl:1: module-docstring: The file has no module docstring. (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#module-docstring)"
-error_integer="test_dir/to_fix_tmp.bzl:1: integer-division: The \"/\" operator for integer division is deprecated in favor of \"//\". (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#integer-division)"
-error_dict="test_dir/to_fix_tmp.bzl:2: unsorted-dict-items: Dictionary items are out of their lexicographical order. (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#unsorted-dict-items)"
-error_cfg="test_dir/to_fix_tmp.bzl:3: attr-cfg: cfg = \"data\" for attr definitions has no effect and should be removed. (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#attr-cfg)"
+error_docstring="test_dir/to_fix_tmp.bzl:1:1: module-docstring: The file has no module docstring. (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#module-docstring)"
+error_integer="test_dir/to_fix_tmp.bzl:1:5: integer-division: The \"/\" operator for integer division is deprecated in favor of \"//\". (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#integer-division)"
+error_dict="test_dir/to_fix_tmp.bzl:2:14: unsorted-dict-items: Dictionary items are out of their lexicographical order. (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#unsorted-dict-items)"
+error_cfg="test_dir/to_fix_tmp.bzl:3:15: attr-cfg: cfg = \"data\" for attr definitions has no effect and should be removed. (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#attr-cfg)"
 
 test_lint () {
   ret=0
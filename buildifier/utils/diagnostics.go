@@ -15,20 +15,20 @@ type Diagnostics struct {
 	Files   []*FileDiagnostics `json:"files"`   // diagnostics per file
 }
 
-// Format formats a Diagnostics object either as plain text or as json
+// Format formats a Diagnostics object as plain text, json or SARIF
 func (d *Diagnostics) Format(format string, verbose bool) string {
 	switch format {
 	case "text", "":
 		var output strings.Builder
 		for _, f := range d.Files {
 			for _, w := range f.Warnings {
-				formatString := "%s:%d: %s: %s (%s)\n"
+				formatString := "%s:%s: %s: %s (%s)\n"
 				if !w.Actionable {
-					formatString = "%s:%d: %s: %s [%s]\n"
+					formatString = "%s:%s: %s: %s [%s]\n"
 				}
 				output.WriteString(fmt.Sprintf(formatString,
 					f.Filename,
-					w.Start.Line,
+					w.Start.buildPosition(),
 					w.Category,
 					w.Message,
 					w.URL))
@@ -57,6 +57,8 @@ func (d *Diagnostics) Format(format string, verbose bool) string {
 			result, _ = json.Marshal(*d)
 		}
 		return string(result) + "\n"
+	case "sarif":
+		return formatSARIF(d, verbose)
 	}
 	return ""
 }
@@ -70,6 +72,18 @@ type FileDiagnostics struct {
 	Rewrites  map[string]int `json:"rewrites,omitempty"`
 }
 
+// Unformatted returns the names of the valid files that need reformatting, in the order
+// they were processed.
+func (d *Diagnostics) Unformatted() []string {
+	var files []string
+	for _, f := range d.Files {
+		if f.Valid && !f.Formatted {
+			files = append(files, f.Filename)
+		}
+	}
+	return files
+}
+
 // SetRewrites adds information about rewrites to the diagnostics
 func (fd *FileDiagnostics) SetRewrites(categories map[string]int) {
 	for category, count := range categories {
@@ -153,3 +167,11 @@ func makePosition(p build.Position) position {
 		Column: p.LineRune,
 	}
 }
+
+// buildPosition converts the position back to build.Position, e.g. to format it.
+func (p position) buildPosition() build.Position {
+	return build.Position{
+		Line:     p.Line,
+		LineRune: p.Column,
+	}
+}
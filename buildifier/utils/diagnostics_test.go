@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/bazelbuild/buildtools/build"
+	"github.com/bazelbuild/buildtools/warn"
+)
+
+func TestFormatText(t *testing.T) {
+	f, err := build.ParseBzl("pkg/foo.bzl", []byte(`"""Module docstring."""
+
+a = b / c
+d = {"b": 2, "a": 1}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := warn.FileWarnings(f, "pkg", []string{"integer-division", "unsorted-dict-items"}, false)
+	fileDiagnostics := NewFileDiagnostics(f.DisplayPath(), findings)
+	fileDiagnostics.Formatted = false
+	diagnostics := NewDiagnostics(fileDiagnostics)
+
+	want := `pkg/foo.bzl:3:5: integer-division: The "/" operator for integer division is deprecated in favor of "//". (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#integer-division)
+pkg/foo.bzl:4:14: unsorted-dict-items: Dictionary items are out of their lexicographical order. (https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#unsorted-dict-items)
+pkg/foo.bzl # reformat
+`
+	if got := diagnostics.Format("text", false); got != want {
+		t.Errorf("Format(text) = %s, want %s", got, want)
+	}
+}
@@ -22,13 +22,13 @@ func ValidateFormat(format, mode *string) error {
 	case "":
 		return nil
 
-	case "text", "json":
+	case "text", "json", "sarif":
 		if *mode != "check" {
 			return fmt.Errorf("cannot specify --format without --type=check")
 		}
 
 	default:
-		return fmt.Errorf("unrecognized format %s; valid types are text, json", *format)
+		return fmt.Errorf("unrecognized format %s; valid types are text, json, sarif", *format)
 	}
 	return nil
 }
@@ -53,7 +53,7 @@ func ValidateModes(mode, lint *string, dflag *bool, additionalModes ...string) e
 	}
 
 	// Check mode.
-	validModes := []string{"check", "diff", "fix", "print_if_changed"}
+	validModes := []string{"check", "diff", "fix", "print_if_changed", "validate"}
 	validModes = append(validModes, additionalModes...)
 
 	if *mode == "" {
@@ -67,9 +67,14 @@ func ValidateModes(mode, lint *string, dflag *bool, additionalModes ...string) e
 	case "":
 		*lint = "off"
 
-	case "off", "warn":
+	case "off":
 		// ok
 
+	case "warn":
+		if *mode == "validate" {
+			return fmt.Errorf("--lint=warn is not compatible with --mode=validate")
+		}
+
 	case "fix":
 		if *mode != "fix" {
 			return fmt.Errorf("--lint=fix is only compatible with --mode=fix")
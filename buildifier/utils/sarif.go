@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"encoding/json"
+	"path/filepath"
+)
+
+// The subset of the SARIF 2.1.0 format (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html)
+// that is needed to report the warnings, e.g. to GitHub code scanning.
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID      string `json:"id"`
+	HelpURI string `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion describes a part of a file, lines and columns start at 1 and the end column
+// points at the character after the region, same as the positions of the warnings.
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// formatSARIF serializes the warnings as a SARIF log with a single run of buildifier,
+// the warning categories are used as rule ids.
+func formatSARIF(d *Diagnostics, verbose bool) string {
+	driver := sarifDriver{
+		Name:           "buildifier",
+		InformationURI: "https://github.com/bazelbuild/buildtools",
+		Rules:          []sarifRule{},
+	}
+	results := []sarifResult{}
+	ruleIndex := make(map[string]int)
+	for _, f := range d.Files {
+		for _, w := range f.Warnings {
+			index, ok := ruleIndex[w.Category]
+			if !ok {
+				index = len(driver.Rules)
+				ruleIndex[w.Category] = index
+				driver.Rules = append(driver.Rules, sarifRule{ID: w.Category, HelpURI: w.URL})
+			}
+			location := sarifLocation{sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.Filename)},
+			}}
+			if w.Start.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   w.Start.Line,
+					StartColumn: w.Start.Column,
+					EndLine:     w.End.Line,
+					EndColumn:   w.End.Column,
+				}
+			}
+			results = append(results, sarifResult{
+				RuleID:    w.Category,
+				RuleIndex: index,
+				Level:     "warning",
+				Message:   sarifMessage{w.Message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{driver}, Results: results}},
+	}
+	var result []byte
+	if verbose {
+		result, _ = json.MarshalIndent(log, "", "    ")
+	} else {
+		result, _ = json.Marshal(log)
+	}
+	return string(result) + "\n"
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/bazelbuild/buildtools/build"
+	"github.com/bazelbuild/buildtools/warn"
+)
+
+func TestFormatSARIF(t *testing.T) {
+	f, err := build.ParseBzl("pkg/foo.bzl", []byte(`"""Module docstring."""
+
+a = b / c
+d = {"b": 2, "a": 1}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := warn.FileWarnings(f, "pkg", []string{"integer-division", "unsorted-dict-items"}, false)
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	diagnostics := NewDiagnostics(NewFileDiagnostics(f.DisplayPath(), findings), NewFileDiagnostics("pkg/BUILD", nil))
+
+	want := `{
+    "version": "2.1.0",
+    "$schema": "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+    "runs": [
+        {
+            "tool": {
+                "driver": {
+                    "name": "buildifier",
+                    "informationUri": "https://github.com/bazelbuild/buildtools",
+                    "rules": [
+                        {
+                            "id": "integer-division",
+                            "helpUri": "https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#integer-division"
+                        },
+                        {
+                            "id": "unsorted-dict-items",
+                            "helpUri": "https://github.com/bazelbuild/buildtools/blob/master/WARNINGS.md#unsorted-dict-items"
+                        }
+                    ]
+                }
+            },
+            "results": [
+                {
+                    "ruleId": "integer-division",
+                    "ruleIndex": 0,
+                    "level": "warning",
+                    "message": {
+                        "text": "The \"/\" operator for integer division is deprecated in favor of \"//\"."
+                    },
+                    "locations": [
+                        {
+                            "physicalLocation": {
+                                "artifactLocation": {
+                                    "uri": "pkg/foo.bzl"
+                                },
+                                "region": {
+                                    "startLine": 3,
+                                    "startColumn": 5,
+                                    "endLine": 3,
+                                    "endColumn": 10
+                                }
+                            }
+                        }
+                    ]
+                },
+                {
+                    "ruleId": "unsorted-dict-items",
+                    "ruleIndex": 1,
+                    "level": "warning",
+                    "message": {
+                        "text": "Dictionary items are out of their lexicographical order."
+                    },
+                    "locations": [
+                        {
+                            "physicalLocation": {
+                                "artifactLocation": {
+                                    "uri": "pkg/foo.bzl"
+                                },
+                                "region": {
+                                    "startLine": 4,
+                                    "startColumn": 14,
+                                    "endLine": 4,
+                                    "endColumn": 20
+                                }
+                            }
+                        }
+                    ]
+                }
+            ]
+        }
+    ]
+}
+`
+	if got := diagnostics.Format("sarif", true); got != want {
+		t.Errorf("Format(sarif) = %s, want %s", got, want)
+	}
+}
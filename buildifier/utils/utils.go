@@ -3,6 +3,9 @@
 package utils
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -39,8 +42,11 @@ func skip(info os.FileInfo) bool {
 
 // ExpandDirectories takes a list of file/directory names and returns a list with file names
 // by traversing each directory recursively and searching for relevant Starlark files.
-func ExpandDirectories(args *[]string) ([]string, error) {
+// Symlinks to directories are only traversed if followSymlinks is true, each directory is
+// visited at most once then, even if it's reachable through several paths.
+func ExpandDirectories(args *[]string, followSymlinks bool) ([]string, error) {
 	files := []string{}
+	visited := make(map[string]bool)
 	for _, arg := range *args {
 		info, err := os.Stat(arg)
 		if err != nil {
@@ -50,15 +56,19 @@ func ExpandDirectories(args *[]string) ([]string, error) {
 			files = append(files, arg)
 			continue
 		}
-		err = filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
-			if skip(info) {
-				return filepath.SkipDir
-			}
-			if isStarlarkFile(info) {
-				files = append(files, path)
-			}
-			return err
-		})
+		if followSymlinks {
+			err = walkFollowingSymlinks(arg, visited, &files)
+		} else {
+			err = filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
+				if skip(info) {
+					return filepath.SkipDir
+				}
+				if isStarlarkFile(info) {
+					files = append(files, path)
+				}
+				return err
+			})
+		}
 		if err != nil {
 			return []string{}, err
 		}
@@ -66,6 +76,48 @@ func ExpandDirectories(args *[]string) ([]string, error) {
 	return files, nil
 }
 
+// walkFollowingSymlinks traverses the directory dir recursively and appends the Starlark files
+// to files. Unlike filepath.Walk it follows symlinks, visited contains the real paths of the
+// directories that have been traversed already to avoid cycles.
+func walkFollowingSymlinks(dir string, visited map[string]bool, files *[]string) error {
+	realPath, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if realPath, err = filepath.Abs(realPath); err != nil {
+		return err
+	}
+	if visited[realPath] {
+		return nil
+	}
+	visited[realPath] = true
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range entries {
+		path := filepath.Join(dir, info.Name())
+		if info.Mode()&os.ModeSymlink != 0 {
+			if info, err = os.Stat(path); err != nil {
+				// Broken symlinks are ignored.
+				continue
+			}
+		}
+		if skip(info) {
+			continue
+		}
+		if info.IsDir() {
+			if err := walkFollowingSymlinks(path, visited, files); err != nil {
+				return err
+			}
+		} else if isStarlarkFile(info) {
+			*files = append(*files, path)
+		}
+	}
+	return nil
+}
+
 // GetParser returns a parser for a given file type
 func GetParser(inputType string) func(filename string, data []byte) (*build.File, error) {
 	switch inputType {
@@ -99,11 +151,23 @@ func GetPackageName(filename string) string {
 
 // Lint calls the linter and returns a list of unresolved findings
 func Lint(f *build.File, pkg, lint string, warningsList *[]string, verbose bool) []*warn.Finding {
+	return LintWithOutput(f, &warn.WarningContext{Pkg: pkg}, lint, warningsList, verbose, os.Stderr)
+}
+
+// LintWithOutput is like Lint but takes the location of the file as a warn.WarningContext,
+// and in verbose mode reports the number of warnings left after fixing to stderr rather than
+// to os.Stderr, e.g. to keep the output of files processed concurrently together.
+func LintWithOutput(f *build.File, ctx *warn.WarningContext, lint string, warningsList *[]string, verbose bool, stderr io.Writer) []*warn.Finding {
 	switch lint {
 	case "warn":
-		return warn.FileWarnings(f, pkg, *warningsList, false)
+		return warn.FileWarningsWithContext(f, ctx, *warningsList, false)
 	case "fix":
-		warn.FixWarnings(f, pkg, *warningsList, verbose)
+		warnings := warn.FileWarningsWithContext(f, ctx, *warningsList, true)
+		if verbose {
+			fmt.Fprintf(stderr, "%s: applied fixes, %d warnings left\n",
+				f.DisplayPath(),
+				len(warnings))
+		}
 	}
 	return nil
 }
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandDirectoriesFollowSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "expand_directories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// root/
+	//   BUILD
+	//   cycle -> root
+	//   link -> other
+	// other/
+	//   BUILD
+	//   lib.bzl
+	root := filepath.Join(dir, "root")
+	other := filepath.Join(dir, "other")
+	for _, d := range []string{root, other} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{filepath.Join(root, "BUILD"), filepath.Join(other, "BUILD"), filepath.Join(other, "lib.bzl")} {
+		if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(other, filepath.Join(root, "link")); err != nil {
+		t.Skipf("can't create symlinks: %v", err)
+	}
+	if err := os.Symlink(root, filepath.Join(root, "cycle")); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{root}
+	files, err := ExpandDirectories(&args, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(root, "BUILD")}; !reflect.DeepEqual(files, want) {
+		t.Errorf("ExpandDirectories() without following symlinks = %q, want %q", files, want)
+	}
+
+	// The linked directory is processed exactly once even if it's also passed explicitly.
+	args = []string{root, other}
+	files, err = ExpandDirectories(&args, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(root, "BUILD"),
+		filepath.Join(root, "link", "BUILD"),
+		filepath.Join(root, "link", "lib.bzl"),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("ExpandDirectories() following symlinks = %q, want %q", files, want)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/bazelbuild/buildtools/build"
 	"github.com/bazelbuild/buildtools/edit"
 	"github.com/bazelbuild/buildtools/tables"
+	"github.com/bazelbuild/buildtools/warn"
 )
 
 var (
@@ -89,6 +90,7 @@ func main() {
 	if !(*shortenLabelsFlag) {
 		build.DisableRewrites = []string{"label"}
 	}
+	warn.RegisterBuildozerFixes()
 	edit.ShortenLabelsFlag = *shortenLabelsFlag
 	edit.DeleteWithComments = *deleteWithComments
 	opts := &edit.Options{
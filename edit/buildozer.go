@@ -187,6 +187,45 @@ func cmdDelete(opts *Options, env CmdEnvironment) (*build.File, error) {
 	return DeleteRule(env.File, env.Rule), nil
 }
 
+// cmdIfAttr runs the subcommands on the rule only if the value of the attribute equals
+// the given value, e.g. "cmd_if_attr testonly True : add tags test : set size small".
+func cmdIfAttr(opts *Options, env CmdEnvironment) (*build.File, error) {
+	var groups [][]string
+	start := 0
+	for i, arg := range env.Args {
+		if arg == ":" {
+			groups = append(groups, env.Args[start:i])
+			start = i + 1
+		}
+	}
+	groups = append(groups, env.Args[start:])
+	if len(groups) < 2 || len(groups[0]) == 0 {
+		return nil, fmt.Errorf("expected '<attr> <value(s)> : <subcommand(s)>', got %q", strings.Join(env.Args, " "))
+	}
+
+	attr, values := groups[0][0], groups[0][1:]
+	if attr == "kind" {
+		if len(values) != 1 || env.Rule.Kind() != values[0] {
+			return nil, nil
+		}
+	} else if value := env.Rule.Attr(attr); value == nil || !build.Equal(value, getAttrValueExpr(attr, values, env)) {
+		return nil, nil
+	}
+
+	var result *build.File
+	for _, subcommand := range groups[1:] {
+		newf, err := Cmd(subcommand).execute(opts, env)
+		if err != nil {
+			return nil, err
+		}
+		if newf != nil {
+			env.File = newf
+			result = newf
+		}
+	}
+	return result, nil
+}
+
 func cmdMove(opts *Options, env CmdEnvironment) (*build.File, error) {
 	oldAttr := env.Args[0]
 	newAttr := env.Args[1]
@@ -255,6 +294,11 @@ func findInsertionIndex(env CmdEnvironment) (bool, int, error) {
 	}
 }
 
+// cmdNewFile doesn't modify the file, the file is created by rewrite if it doesn't exist.
+func cmdNewFile(opts *Options, env CmdEnvironment) (*build.File, error) {
+	return nil, nil
+}
+
 func cmdNewLoad(opts *Options, env CmdEnvironment) (*build.File, error) {
 	from := env.Args[1:]
 	to := append([]string{}, from...)
@@ -269,6 +313,22 @@ func cmdNewLoad(opts *Options, env CmdEnvironment) (*build.File, error) {
 	return env.File, nil
 }
 
+func cmdNormalizeLabels(opts *Options, env CmdEnvironment) (*build.File, error) {
+	var toAbsolute bool
+	switch env.Args[0] {
+	case "absolute":
+		toAbsolute = true
+	case "short":
+		toAbsolute = false
+	default:
+		return nil, fmt.Errorf("unknown label form %q, expected \"absolute\" or \"short\"", env.Args[0])
+	}
+	if normalizeRuleLabels(env.Rule, env.Pkg, toAbsolute) == 0 {
+		return nil, nil
+	}
+	return env.File, nil
+}
+
 func cmdPrint(opts *Options, env CmdEnvironment) (*build.File, error) {
 	format := env.Args
 	if len(format) == 0 {
@@ -539,6 +599,23 @@ func cmdDictRemove(opts *Options, env CmdEnvironment) (*build.File, error) {
 	return env.File, nil
 }
 
+func cmdWrapSelect(opts *Options, env CmdEnvironment) (*build.File, error) {
+	attr := env.Args[0]
+	condition := env.Args[1]
+	as := env.Rule.AttrDefn(attr)
+	if as == nil {
+		return nil, fmt.Errorf("no attribute %s found in rule %s", attr, env.Rule.Name())
+	}
+	if call, ok := as.RHS.(*build.CallExpr); ok {
+		if fn, ok := call.X.(*build.Ident); ok && fn.Name == "select" {
+			// Already conditional.
+			return nil, nil
+		}
+	}
+	as.RHS = WrapInSelect(as.RHS, condition)
+	return env.File, nil
+}
+
 func copyAttributeBetweenRules(env CmdEnvironment, attrName string, from string) (*build.File, error) {
 	fromRule := FindRuleByName(env.File, from)
 	if fromRule == nil {
@@ -559,12 +636,39 @@ func copyAttributeBetweenRules(env CmdEnvironment, attrName string, from string)
 }
 
 func cmdFix(opts *Options, env CmdEnvironment) (*build.File, error) {
-	// Fix the whole file
+	// Linter warning categories (possibly comma-separated) are fixed in the whole file,
+	// all other arguments are names of buildozer fixes.
+	var fixes []string
+	hasWarnings, warningsFixed := false, false
+	for _, arg := range env.Args {
+		for _, name := range strings.Split(arg, ",") {
+			if fn, ok := WarningFixes[name]; ok {
+				hasWarnings = true
+				warningsFixed = fn(env.File, env.Pkg) || warningsFixed
+			} else if name != "" {
+				fixes = append(fixes, name)
+			}
+		}
+	}
+	if hasWarnings && len(fixes) == 0 {
+		if warningsFixed {
+			return env.File, nil
+		}
+		return nil, nil
+	}
+
+	var f *build.File
 	if env.Rule.Kind() == "package" {
-		return FixFile(env.File, env.Pkg, env.Args), nil
+		// Fix the whole file
+		f = FixFile(env.File, env.Pkg, fixes)
+	} else {
+		// Fix a specific rule
+		f = FixRule(env.File, env.Pkg, env.Rule, fixes)
 	}
-	// Fix a specific rule
-	return FixRule(env.File, env.Pkg, env.Rule, env.Args), nil
+	if f == nil && warningsFixed {
+		return env.File, nil
+	}
+	return f, nil
 }
 
 // CommandInfo provides a command function and info on incoming arguments.
@@ -587,6 +691,8 @@ var AllCommands = map[string]CommandInfo{
 	"fix":               {cmdFix, true, 0, -1, "<fix(es)>?"},
 	"move":              {cmdMove, true, 3, -1, "<old_attr> <new_attr> <value(s)>"},
 	"new":               {cmdNew, false, 2, 4, "<rule_kind> <rule_name> [(before|after) <relative_rule_name>]"},
+	"new_file":          {cmdNewFile, false, 0, 0, ""},
+	"normalize_labels":  {cmdNormalizeLabels, true, 1, 1, "<absolute|short>"},
 	"print":             {cmdPrint, true, 0, -1, "<attribute(s)>"},
 	"remove":            {cmdRemove, true, 1, -1, "<attr> <value(s)>"},
 	"rename":            {cmdRename, true, 2, 2, "<old_attr> <new_attr>"},
@@ -599,6 +705,13 @@ var AllCommands = map[string]CommandInfo{
 	"dict_add":          {cmdDictAdd, true, 2, -1, "<attr> <(key:value)(s)>"},
 	"dict_set":          {cmdDictSet, true, 2, -1, "<attr> <(key:value)(s)>"},
 	"dict_remove":       {cmdDictRemove, true, 2, -1, "<attr> <key(s)>"},
+	"wrap_select":       {cmdWrapSelect, true, 2, 2, "<attr> <condition>"},
+}
+
+func init() {
+	// Registered separately because cmd_if_attr runs other commands from AllCommands,
+	// which would be an initialization cycle otherwise.
+	AllCommands["cmd_if_attr"] = CommandInfo{cmdIfAttr, true, 3, -1, "<attr> <value(s)> : <subcommand(s)>"}
 }
 
 func expandTargets(f *build.File, rule string) ([]*build.Rule, error) {
@@ -640,6 +753,101 @@ func filterRules(opts *Options, rules []*build.Rule) (result []*build.Rule) {
 	return
 }
 
+// A Command is an edit that can be applied to the rules of a file with Execute.
+type Command interface {
+	execute(opts *Options, env CmdEnvironment) (*build.File, error)
+	perRule() bool
+}
+
+// Cmd is a command of the buildozer command-line syntax, e.g. Cmd{"add", "deps", ":foo"}.
+type Cmd []string
+
+func (c Cmd) info() (CommandInfo, error) {
+	if len(c) == 0 {
+		return CommandInfo{}, errors.New("empty command")
+	}
+	cmd, ok := AllCommands[c[0]]
+	if !ok {
+		return CommandInfo{}, fmt.Errorf("unknown command %q", c[0])
+	}
+	if count := len(c) - 1; count < cmd.MinArg || (cmd.MaxArg != -1 && count > cmd.MaxArg) {
+		return CommandInfo{}, fmt.Errorf("wrong number of arguments for command %q: %d", c[0], count)
+	}
+	return cmd, nil
+}
+
+func (c Cmd) execute(opts *Options, env CmdEnvironment) (*build.File, error) {
+	cmd, err := c.info()
+	if err != nil {
+		return nil, err
+	}
+	env.Args = c[1:]
+	return cmd.Fn(opts, env)
+}
+
+func (c Cmd) perRule() bool {
+	cmd, err := c.info()
+	return err != nil || cmd.PerRule
+}
+
+// TransformAttr replaces the value of the attribute Attr of each matched rule with
+// the result of Fn. If Fn returns nil, the attribute is removed. Rules that don't
+// have the attribute are left untouched.
+// Unlike Cmd, it's only available through the Go API.
+type TransformAttr struct {
+	Attr string
+	Fn   func(build.Expr) build.Expr
+}
+
+func (t TransformAttr) execute(opts *Options, env CmdEnvironment) (*build.File, error) {
+	val := env.Rule.Attr(t.Attr)
+	if val == nil {
+		return nil, nil
+	}
+	if newVal := t.Fn(val); newVal != nil {
+		env.Rule.SetAttr(t.Attr, newVal)
+	} else {
+		env.Rule.DelAttr(t.Attr)
+	}
+	return env.File, nil
+}
+
+func (t TransformAttr) perRule() bool {
+	return true
+}
+
+// Execute applies the commands to the rules of f that match target, which is either
+// a rule name or one of the wildcards supported by buildozer ("all", "%kind", "%<line>").
+// pkg is the name of the package of the file. The file is modified in place,
+// Execute reports whether it has been changed, i.e. whether it's formatted differently.
+func Execute(opts *Options, f *build.File, pkg, target string, cmds ...Command) (bool, error) {
+	targets, err := expandTargets(f, target)
+	if err != nil {
+		return false, err
+	}
+	targets = filterRules(opts, targets)
+
+	vars := map[string]*build.AssignExpr{}
+	if opts.EditVariables {
+		vars = getGlobalVariables(f.Stmt)
+	}
+	// Commands don't report whether they have actually changed anything (e.g. adding a
+	// dependency that is already there), so compare the outputs like rewrite does.
+	data := build.Format(f)
+	for _, cmd := range cmds {
+		cmdTargets := targets
+		if !cmd.perRule() {
+			cmdTargets = []*build.Rule{nil}
+		}
+		for _, r := range cmdTargets {
+			if _, err := cmd.execute(opts, CmdEnvironment{f, r, vars, pkg, nil, &apipb.Output_Record{}}); err != nil {
+				return !bytes.Equal(data, build.Format(f)), err
+			}
+		}
+	}
+	return !bytes.Equal(data, build.Format(f)), nil
+}
+
 // command contains a list of tokens that describe a buildozer command.
 type command struct {
 	tokens []string
@@ -751,6 +959,19 @@ var buildFileNamesSet = map[string]bool{
 	"BUCK":        true,
 }
 
+// createsFile reports whether the commands contain `new_file`, i.e. the BUILD file
+// should be created if it doesn't exist.
+func createsFile(commandsForFile commandsForFile) bool {
+	for _, commands := range commandsForFile.commands {
+		for _, cmd := range commands.commands {
+			if cmd.tokens[0] == "new_file" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // rewrite parses the BUILD file for the given file, transforms the AST,
 // and write the changes back in the file (or on stdout).
 func rewrite(opts *Options, commandsForFile commandsForFile) *rewriteResult {
@@ -758,6 +979,7 @@ func rewrite(opts *Options, commandsForFile commandsForFile) *rewriteResult {
 	var data []byte
 	var err error
 	var fi os.FileInfo
+	created := false
 	records := []*apipb.Output_Record{}
 	if name == stdinPackageName { // read on stdin
 		data, err = ioutil.ReadAll(os.Stdin)
@@ -783,6 +1005,12 @@ func rewrite(opts *Options, commandsForFile commandsForFile) *rewriteResult {
 		if err != nil {
 			data, fi, err = file.ReadFile(name)
 		}
+		if err != nil && createsFile(commandsForFile) {
+			if _, statErr := os.Stat(origName); os.IsNotExist(statErr) {
+				name, data, err = origName, nil, nil
+				created = true
+			}
+		}
 		if err != nil {
 			err = errors.New("file not found or not readable")
 			return &rewriteResult{file: origName, errs: []error{err}}
@@ -799,7 +1027,7 @@ func rewrite(opts *Options, commandsForFile commandsForFile) *rewriteResult {
 		vars = getGlobalVariables(f.Stmt)
 	}
 	var errs []error
-	changed := false
+	changed := created
 	for _, commands := range commandsForFile.commands {
 		target := commands.target
 		commands := commands.commands
@@ -862,11 +1090,15 @@ func rewrite(opts *Options, commandsForFile commandsForFile) *rewriteResult {
 		return &rewriteResult{file: name, errs: errs, records: records}
 	}
 
-	if bytes.Equal(data, ndata) {
+	if !created && bytes.Equal(data, ndata) {
 		return &rewriteResult{file: name, errs: errs, records: records}
 	}
 
-	if err := EditFile(fi, name); err != nil {
+	if created {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return &rewriteResult{file: name, errs: []error{err}, records: records}
+		}
+	} else if err := EditFile(fi, name); err != nil {
 		return &rewriteResult{file: name, errs: []error{err}, records: records}
 	}
 
@@ -0,0 +1,408 @@
+package edit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bazelbuild/buildtools/build"
+)
+
+func TestExecuteTransformAttr(t *testing.T) {
+	f, err := build.ParseBuild("BUILD", []byte(`go_library(
+    name = "foo",
+    deps = [
+        ":bar",
+        "//third_party/baz",
+        "//third_party/qux",
+    ],
+)
+
+go_library(
+    name = "bar",
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removeThirdParty := TransformAttr{
+		Attr: "deps",
+		Fn: func(expr build.Expr) build.Expr {
+			list, ok := expr.(*build.ListExpr)
+			if !ok {
+				return expr
+			}
+			var kept []build.Expr
+			for _, item := range list.List {
+				if str, ok := item.(*build.StringExpr); ok && strings.HasPrefix(str.Value, "//third_party/") {
+					continue
+				}
+				kept = append(kept, item)
+			}
+			list.List = kept
+			return list
+		},
+	}
+	changed, err := Execute(NewOpts(), f, "pkg", "all", removeThirdParty, Cmd{"set", "visibility", "//visibility:public"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("Execute() reported no changes")
+	}
+
+	deps := f.Rules("")[0].AttrStrings("deps")
+	if len(deps) != 1 || deps[0] != ":bar" {
+		t.Errorf("got deps %v, want [:bar]", deps)
+	}
+	if deps := f.Rules("")[1].Attr("deps"); deps != nil {
+		t.Errorf("got deps %s for a rule without deps, want none", build.FormatString(deps))
+	}
+	for _, r := range f.Rules("") {
+		if got := r.AttrStrings("visibility"); len(got) != 1 || got[0] != "//visibility:public" {
+			t.Errorf("got visibility %v for %q, want [//visibility:public]", got, r.Name())
+		}
+	}
+}
+
+func TestExecuteUnchanged(t *testing.T) {
+	f, err := build.ParseBuild("BUILD", []byte(`go_library(
+    name = "foo",
+    deps = [":bar"],
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cmd := range []Command{
+		TransformAttr{Attr: "deps", Fn: func(expr build.Expr) build.Expr { return expr }},
+		TransformAttr{Attr: "srcs", Fn: func(expr build.Expr) build.Expr { return nil }},
+		Cmd{"add", "deps", ":bar"},
+		Cmd{"remove", "srcs", "foo.go"},
+	} {
+		changed, err := Execute(NewOpts(), f, "pkg", "foo", cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed {
+			t.Errorf("Execute(%v) reported changes, want none", cmd)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	f, err := build.ParseBuild("BUILD", []byte(`go_library(name = "foo")`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		target string
+		cmd    Command
+	}{
+		{"bar", Cmd{"set", "srcs", "foo.go"}},
+		{"foo", Cmd{"unknown"}},
+		{"foo", Cmd{"rename", "srcs"}},
+	} {
+		if _, err := Execute(NewOpts(), f, "pkg", tt.target, tt.cmd); err == nil {
+			t.Errorf("Execute(%q, %v): got no error", tt.target, tt.cmd)
+		}
+	}
+}
+
+func TestExecuteIfAttr(t *testing.T) {
+	f, err := build.ParseBuild("BUILD", []byte(`java_library(
+    name = "testutil",
+    testonly = True,
+)
+
+java_library(
+    name = "lib",
+    testonly = False,
+)
+
+java_library(
+    name = "other",
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := Cmd{"cmd_if_attr", "testonly", "True", ":", "add", "tags", "test", ":", "set", "visibility", "//visibility:private"}
+	changed, err := Execute(NewOpts(), f, "pkg", "all", cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("Execute() reported no changes")
+	}
+	want := `java_library(
+    name = "testutil",
+    testonly = True,
+    tags = ["test"],
+    visibility = ["//visibility:private"],
+)
+
+java_library(
+    name = "lib",
+    testonly = False,
+)
+
+java_library(
+    name = "other",
+)
+`
+	if got := string(build.Format(f)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// No rule matches the predicate.
+	changed, err = Execute(NewOpts(), f, "pkg", "all", Cmd{"cmd_if_attr", "kind", "cc_library", ":", "delete"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || len(f.Rules("")) != 3 {
+		t.Errorf("Execute() changed the file for a predicate that doesn't match")
+	}
+
+	for _, cmd := range []Cmd{
+		{"cmd_if_attr", "testonly", "True", "add", "tags", "test"},
+		{"cmd_if_attr", "testonly", "True", ":", "unknown"},
+		{"cmd_if_attr", "testonly", "True", ":"},
+	} {
+		if _, err := Execute(NewOpts(), f, "pkg", "testutil", cmd); err == nil {
+			t.Errorf("Execute(%v): got no error", cmd)
+		}
+	}
+}
+
+func TestBuildozerNewFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "new_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "WORKSPACE"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := NewOpts()
+	opts.RootDir = dir
+	opts.Quiet = true
+
+	// Without new_file, a missing BUILD file is an error.
+	if ret := Buildozer(opts, []string{"new java_library lib", "//pkg/new:__pkg__"}); ret != 2 {
+		t.Errorf("Buildozer() without new_file = %d, want 2", ret)
+	}
+
+	if ret := Buildozer(opts, []string{"new_file", "new java_library lib", "//pkg/new:__pkg__"}); ret != 0 {
+		t.Fatalf("Buildozer() = %d, want 0", ret)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "pkg", "new", "BUILD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `java_library(name = "lib")
+`
+	if string(data) != want {
+		t.Errorf("got BUILD file:\n%s\nwant:\n%s", data, want)
+	}
+
+	// An existing file is not affected by new_file.
+	if ret := Buildozer(opts, []string{"new_file", "new java_library lib2", "//pkg/new:__pkg__"}); ret != 0 {
+		t.Fatalf("Buildozer() on an existing file = %d, want 0", ret)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(dir, "pkg", "new", "BUILD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `java_library(name = "lib")
+
+java_library(name = "lib2")
+`
+	if string(data) != want {
+		t.Errorf("got BUILD file:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestExecuteWrapSelect(t *testing.T) {
+	f, err := build.ParseBuild("BUILD", []byte(`cc_library(
+    name = "lib",
+    deps = [":dep"],
+    copts = select({
+        ":opt": ["-O2"],
+        "//conditions:default": [],
+    }),
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := Execute(NewOpts(), f, "pkg", "lib", Cmd{"wrap_select", "deps", "//conditions:default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("Execute() reported no changes")
+	}
+	want := `cc_library(
+    name = "lib",
+    deps = select({
+        "//conditions:default": [":dep"],
+    }),
+    copts = select({
+        ":opt": ["-O2"],
+        "//conditions:default": [],
+    }),
+)
+`
+	if got := string(build.Format(f)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Selects aren't wrapped again.
+	changed, err = Execute(NewOpts(), f, "pkg", "lib", Cmd{"wrap_select", "copts", "//conditions:default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("Execute() changed a select()")
+	}
+
+	if _, err := Execute(NewOpts(), f, "pkg", "lib", Cmd{"wrap_select", "srcs", "//conditions:default"}); err == nil {
+		t.Error("Execute() succeeded for a missing attribute")
+	}
+}
+
+func TestBuildozerNormalizeLabels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "normalize_labels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "WORKSPACE"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	buildFile := filepath.Join(dir, "pkg", "BUILD")
+	if err := ioutil.WriteFile(buildFile, []byte(`java_library(
+    name = "lib",
+    deps = [":dep"],
+)
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := NewOpts()
+	opts.RootDir = dir
+	opts.Quiet = true
+
+	if ret := Buildozer(opts, []string{"normalize_labels absolute", "//pkg:lib"}); ret != 0 {
+		t.Fatalf("Buildozer() = %d, want 0", ret)
+	}
+	data, err := ioutil.ReadFile(buildFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `java_library(
+    name = "lib",
+    deps = ["//pkg:dep"],
+)
+`
+	if string(data) != want {
+		t.Errorf("got BUILD file:\n%s\nwant:\n%s", data, want)
+	}
+
+	if ret := Buildozer(opts, []string{"normalize_labels short", "//pkg:lib"}); ret != 0 {
+		t.Fatalf("Buildozer() = %d, want 0", ret)
+	}
+	data, err = ioutil.ReadFile(buildFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `java_library(
+    name = "lib",
+    deps = [":dep"],
+)
+`
+	if string(data) != want {
+		t.Errorf("got BUILD file:\n%s\nwant:\n%s", data, want)
+	}
+
+	if ret := Buildozer(opts, []string{"normalize_labels relative", "//pkg:lib"}); ret != 2 {
+		t.Errorf("Buildozer() with an unknown form = %d, want 2", ret)
+	}
+}
+
+func TestBuildozerCommandsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "commands_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "WORKSPACE"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	buildFile := filepath.Join(dir, "pkg", "BUILD")
+	if err := ioutil.WriteFile(buildFile, []byte(`java_library(name = "a")
+
+java_library(name = "b")
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commands := filepath.Join(dir, "commands")
+	if err := ioutil.WriteFile(commands, []byte(`add deps :x|//pkg:a
+set testonly True|add deps :y|//pkg:b
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var writes int32
+	defer func(editFile func(os.FileInfo, string) error) { EditFile = editFile }(EditFile)
+	EditFile = func(fi os.FileInfo, name string) error {
+		atomic.AddInt32(&writes, 1)
+		return nil
+	}
+
+	opts := NewOpts()
+	opts.RootDir = dir
+	opts.Quiet = true
+	opts.CommandsFile = commands
+	if ret := Buildozer(opts, nil); ret != 0 {
+		t.Fatalf("Buildozer() = %d, want 0", ret)
+	}
+	if writes != 1 {
+		t.Errorf("the BUILD file was written %d times, want 1", writes)
+	}
+
+	data, err := ioutil.ReadFile(buildFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `java_library(
+    name = "a",
+    deps = [":x"],
+)
+
+java_library(
+    name = "b",
+    testonly = True,
+    deps = [":y"],
+)
+`
+	if string(data) != want {
+		t.Errorf("got BUILD file:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+// Migration of WORKSPACE files to MODULE.bazel files
+
+package edit
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/bazelbuild/buildtools/build"
+)
+
+// A Warning describes a part of a file that couldn't be processed automatically.
+type Warning struct {
+	Start   build.Position
+	End     build.Position
+	Message string
+}
+
+func makeWarning(expr build.Expr, format string, args ...interface{}) Warning {
+	start, end := expr.Span()
+	return Warning{start, end, fmt.Sprintf(format, args...)}
+}
+
+// wellKnownModules maps names of repositories commonly declared in WORKSPACE files
+// to the names of the corresponding modules in the Bazel Central Registry.
+var wellKnownModules = map[string]string{
+	"bazel_skylib":          "bazel_skylib",
+	"com_google_absl":       "abseil-cpp",
+	"com_google_googletest": "googletest",
+	"com_google_protobuf":   "protobuf",
+	"io_bazel_rules_go":     "rules_go",
+	"platforms":             "platforms",
+	"rules_cc":              "rules_cc",
+	"rules_java":            "rules_java",
+	"rules_pkg":             "rules_pkg",
+	"rules_proto":           "rules_proto",
+	"rules_python":          "rules_python",
+}
+
+// repositoryLoads lists the files from which the migrated repository rules are loaded.
+var repositoryLoads = map[string]bool{
+	"@bazel_tools//tools/build_defs/repo:http.bzl": true,
+	"@bazel_tools//tools/build_defs/repo:git.bzl":  true,
+}
+
+var versionRegexp = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// repositoryVersion guesses the version of a repository from the attributes of
+// an `http_archive` or `git_repository` rule, it returns "" if there's no version.
+func repositoryVersion(rule *build.Rule) string {
+	candidates := []string{rule.AttrString("strip_prefix"), rule.AttrString("tag"), rule.AttrString("url")}
+	candidates = append(candidates, rule.AttrStrings("urls")...)
+	for _, candidate := range candidates {
+		if version := versionRegexp.FindString(candidate); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// MigrateWorkspaceToModule converts the repository declarations of a WORKSPACE file
+// to the statements of a MODULE.bazel file. The `workspace` declaration becomes a
+// `module` declaration, and `http_archive` and `git_repository` rules of well-known
+// repositories become `bazel_dep` statements, their versions are derived from the
+// archive names or tags. Everything else, e.g. calls to macros defining dependencies,
+// can't be migrated automatically and is reported as a warning.
+//
+// The WORKSPACE file is not modified, the returned file is formatted like a WORKSPACE file.
+func MigrateWorkspaceToModule(ws *build.File) (*build.File, []Warning) {
+	module := &build.File{Path: "MODULE.bazel", Type: build.TypeWorkspace}
+	var warnings []Warning
+
+	for _, stmt := range ws.Stmt {
+		switch stmt := stmt.(type) {
+		case *build.CommentBlock:
+			continue
+		case *build.LoadStmt:
+			if repositoryLoads[stmt.Module.Value] {
+				continue
+			}
+			warnings = append(warnings, makeWarning(stmt, "The load statement of %q can't be migrated.", stmt.Module.Value))
+			continue
+		case *build.CallExpr:
+			rule := ws.Rule(stmt)
+			switch rule.Kind() {
+			case "workspace":
+				if name := rule.Name(); name != "" {
+					module.Stmt = append(module.Stmt, moduleCall("module", "name", name))
+					continue
+				}
+			case "http_archive", "git_repository":
+				name := rule.Name()
+				moduleName, ok := wellKnownModules[name]
+				if !ok {
+					warnings = append(warnings, makeWarning(stmt, "The repository %q is not known to be available as a module.", name))
+					continue
+				}
+				version := repositoryVersion(rule)
+				if version == "" {
+					warnings = append(warnings, makeWarning(stmt, "The version of the repository %q can't be determined.", name))
+					continue
+				}
+				call := moduleCall("bazel_dep", "name", moduleName, "version", version)
+				if moduleName != name {
+					// Keep the old name so that the labels in BUILD files don't need to be changed
+					call.List = append(call.List, moduleArg("repo_name", name))
+				}
+				module.Stmt = append(module.Stmt, call)
+				continue
+			}
+		}
+		warnings = append(warnings, makeWarning(stmt, "The statement can't be migrated: %s", build.FormatString(stmt)))
+	}
+	return module, warnings
+}
+
+// moduleCall creates a call with string keyword arguments, given as name-value pairs.
+func moduleCall(kind string, args ...string) *build.CallExpr {
+	call := &build.CallExpr{X: build.NewIdent(kind)}
+	for i := 0; i+1 < len(args); i += 2 {
+		call.List = append(call.List, moduleArg(args[i], args[i+1]))
+	}
+	return call
+}
+
+func moduleArg(name, value string) *build.AssignExpr {
+	return &build.AssignExpr{LHS: build.NewIdent(name), Op: "=", RHS: build.NewString(value)}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2016 Google Inc. All Rights Reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package edit
+
+import (
+	"testing"
+
+	"github.com/bazelbuild/buildtools/build"
+)
+
+func TestMigrateWorkspaceToModule(t *testing.T) {
+	ws, err := build.ParseWorkspace("WORKSPACE", []byte(`workspace(name = "my_project")
+
+load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")
+
+http_archive(
+    name = "bazel_skylib",
+    sha256 = "66ffd9315665bfaafc96b52278f57c7e2dd09f5ede279ea6d39b2be471e7e3aa",
+    urls = ["https://github.com/bazelbuild/bazel-skylib/releases/download/1.4.2/bazel-skylib-1.4.2.tar.gz"],
+)
+
+# Go rules
+http_archive(
+    name = "io_bazel_rules_go",
+    strip_prefix = "rules_go-0.41.0",
+    urls = ["https://example.com/rules_go.tar.gz"],
+)
+
+http_archive(
+    name = "my_dep",
+    urls = ["https://example.com/my_dep-1.0.tar.gz"],
+)
+
+load("@io_bazel_rules_go//go:deps.bzl", "go_rules_dependencies")
+
+go_rules_dependencies()
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	module, warnings := MigrateWorkspaceToModule(ws)
+
+	want := `module(name = "my_project")
+
+bazel_dep(
+    name = "bazel_skylib",
+    version = "1.4.2",
+)
+
+bazel_dep(
+    name = "rules_go",
+    version = "0.41.0",
+    repo_name = "io_bazel_rules_go",
+)
+`
+	if got := string(build.Format(module)); got != want {
+		t.Errorf("MigrateWorkspaceToModule() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantWarnings := []struct {
+		line    int
+		message string
+	}{
+		{18, `The repository "my_dep" is not known to be available as a module.`},
+		{23, `The load statement of "@io_bazel_rules_go//go:deps.bzl" can't be migrated.`},
+		{25, `The statement can't be migrated: go_rules_dependencies()`},
+	}
+	if len(warnings) != len(wantWarnings) {
+		t.Fatalf("got %d warnings, want %d: %v", len(warnings), len(wantWarnings), warnings)
+	}
+	for i, w := range wantWarnings {
+		if warnings[i].Start.Line != w.line || warnings[i].Message != w.message {
+			t.Errorf("warning #%d: got %d: %q, want %d: %q", i, warnings[i].Start.Line, warnings[i].Message, w.line, w.message)
+		}
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -105,6 +104,117 @@ func LabelsEqual(label1, label2, pkg string) bool {
 	return str1 == str2
 }
 
+// resolveLabel converts a label to its absolute form, e.g. "@repo//pkg:name",
+// relative labels are resolved against the package pkg. Labels of the main
+// repository written as "@//pkg:name" keep their prefix.
+func resolveLabel(label, pkg string) string {
+	repo, labelPkg, rule := ParseLabel(label)
+	if strings.HasPrefix(label, "@") {
+		return "@" + repo + "//" + labelPkg + ":" + rule
+	}
+	if !strings.HasPrefix(label, "//") {
+		labelPkg = pkg
+	}
+	return "//" + labelPkg + ":" + rule
+}
+
+// isBareFileName returns true if the label is a file name relative to the package,
+// such as "lib.cc" or "data/input.txt", written without a colon.
+func isBareFileName(label string) bool {
+	if strings.HasPrefix(label, "@") || strings.HasPrefix(label, "//") || strings.Contains(label, ":") {
+		return false
+	}
+	return strings.Contains(label, "/") || strings.Contains(label, ".")
+}
+
+// filePackage returns the package of a file based on its path relative to the workspace.
+func filePackage(f *build.File) string {
+	pkg := filepath.ToSlash(filepath.Dir(f.Path))
+	if pkg == "." {
+		return ""
+	}
+	return pkg
+}
+
+// walkRuleLabels calls fn on each string in the label attributes of the rule (e.g. "deps",
+// "data", "visibility"), including the ones inside selects but not the select conditions.
+func walkRuleLabels(rule *build.Rule, fn func(str *build.StringExpr)) {
+	for _, attr := range rule.AttrKeys() {
+		if !tables.IsLabelArg[attr] || tables.LabelBlacklist[rule.Kind()+"."+attr] {
+			continue
+		}
+		build.Walk(rule.Attr(attr), func(x build.Expr, stk []build.Expr) {
+			str, ok := x.(*build.StringExpr)
+			if !ok || str.Value == "" {
+				return
+			}
+			// Keys of select dicts are configuration conditions.
+			if len(stk) > 0 {
+				if kv, ok := stk[len(stk)-1].(*build.KeyValueExpr); ok && kv.Key == x {
+					return
+				}
+			}
+			fn(str)
+		})
+	}
+}
+
+// AllLabels returns all labels used in the label attributes of the rules of the file
+// (e.g. "deps", "data", "visibility"), including the ones inside selects. The labels
+// are resolved against the package of the file and listed in the order of their
+// first appearance.
+func AllLabels(f *build.File) []string {
+	pkg := filePackage(f)
+
+	var labels []string
+	seen := make(map[string]bool)
+	for _, rule := range f.Rules("") {
+		walkRuleLabels(rule, func(str *build.StringExpr) {
+			label := resolveLabel(str.Value, pkg)
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		})
+	}
+	return labels
+}
+
+// NormalizeLabels rewrites the labels in the label attributes of all rules of the file
+// either to their absolute form ("//pkg:name") or to the shortest form (see ShortenLabel).
+// Relative labels are resolved against the package of the file, except for bare file
+// names such as "lib.cc", which are kept as they are in both forms. Returns the number
+// of changed labels.
+func NormalizeLabels(f *build.File, toAbsolute bool) int {
+	pkg := filePackage(f)
+	n := 0
+	for _, rule := range f.Rules("") {
+		n += normalizeRuleLabels(rule, pkg, toAbsolute)
+	}
+	return n
+}
+
+// normalizeRuleLabels is the same as NormalizeLabels but for a single rule from the package pkg.
+func normalizeRuleLabels(rule *build.Rule, pkg string, toAbsolute bool) int {
+	n := 0
+	walkRuleLabels(rule, func(str *build.StringExpr) {
+		var label string
+		if isBareFileName(str.Value) {
+			return
+		}
+		if toAbsolute {
+			label = resolveLabel(str.Value, pkg)
+		} else {
+			label = ShortenLabel(str.Value, pkg)
+		}
+		if label != str.Value {
+			str.Value = label
+			n++
+		}
+	})
+	return n
+}
+
 // isFile returns true if the path refers to a regular file after following
 // symlinks.
 func isFile(path string) bool {
@@ -291,6 +401,8 @@ func FindRuleByName(f *build.File, name string) *build.Rule {
 }
 
 // IndexOfRuleByName returns the index (in f.Stmt) of the CallExpr which defines a rule named `name`, or -1 if it doesn't exist.
+// Only top-level statements are considered, rules nested in `if` or `for` blocks are not found.
+// The index can be passed to InsertAfter to insert new statements right after the rule.
 func IndexOfRuleByName(f *build.File, name string) (int, *build.Rule) {
 	linenum := -1
 	if strings.HasPrefix(name, "%") {
@@ -722,6 +834,22 @@ func DictionaryDelete(dict *build.DictExpr, key string) (deleted build.Expr) {
 	return deleted
 }
 
+// WrapInSelect returns a select() call with a single branch, mapping the condition
+// (usually "//conditions:default") to the value.
+func WrapInSelect(value build.Expr, defaultCondition string) *build.CallExpr {
+	return &build.CallExpr{
+		X: &build.Ident{Name: "select"},
+		List: []build.Expr{
+			&build.DictExpr{
+				List: []build.Expr{
+					&build.KeyValueExpr{Key: &build.StringExpr{Value: defaultCondition}, Value: value},
+				},
+				ForceMultiLine: true,
+			},
+		},
+	}
+}
+
 // RenameAttribute renames an attribute in a rule.
 func RenameAttribute(r *build.Rule, oldName, newName string) error {
 	if r.Attr(newName) != nil {
@@ -786,92 +914,8 @@ func UsedSymbols(stmt build.Expr) map[string]bool {
 	return symbols
 }
 
-func newLoad(location string, from, to []string) *build.LoadStmt {
-	load := &build.LoadStmt{
-		Module: &build.StringExpr{
-			Value: location,
-		},
-		ForceCompact: true,
-	}
-	for i := range from {
-		load.From = append(load.From, &build.Ident{Name: from[i]})
-		load.To = append(load.To, &build.Ident{Name: to[i]})
-	}
-	return load
-}
-
-// appendLoad tries to find an existing load location and append symbols to it.
-func appendLoad(stmts []build.Expr, location string, from, to []string) bool {
-	symbolsToLoad := make(map[string]string)
-	for i, s := range to {
-		symbolsToLoad[s] = from[i]
-	}
-	var lastLoad *build.LoadStmt
-	for _, s := range stmts {
-		load, ok := s.(*build.LoadStmt)
-		if !ok {
-			continue
-		}
-		if load.Module.Value != location {
-			continue // Loads a different file.
-		}
-		for _, ident := range load.To {
-			delete(symbolsToLoad, ident.Name) // Already loaded.
-		}
-		// Remember the last insert location, but potentially remove more symbols
-		// that are already loaded in other subsequent calls.
-		lastLoad = load
-	}
-
-	if lastLoad == nil {
-		return false
-	}
-
-	// Append the remaining loads to the last load location.
-	sortedSymbols := []string{}
-	for s := range symbolsToLoad {
-		sortedSymbols = append(sortedSymbols, s)
-	}
-	sort.Strings(sortedSymbols)
-	for _, s := range sortedSymbols {
-		lastLoad.From = append(lastLoad.From, &build.Ident{Name: symbolsToLoad[s]})
-		lastLoad.To = append(lastLoad.To, &build.Ident{Name: s})
-	}
-	return true
-}
-
-// InsertLoad inserts a load statement at the top of the list of statements.
-// The load statement is constructed using a string location and two slices of from- and to-symbols.
-// The function panics if the slices aren't of the same lentgh. Symbols that are already loaded
-// from the given filepath are ignored. If stmts already contains a load for the
-// location in arguments, appends the symbols to load to it.
+// InsertLoad inserts a load statement at the top of the list of statements, see
+// build.InsertLoad.
 func InsertLoad(stmts []build.Expr, location string, from, to []string) []build.Expr {
-	if len(from) != len(to) {
-		panic(fmt.Errorf("length mismatch: %v (from) and %v (to)", len(from), len(to)))
-	}
-
-	if appendLoad(stmts, location, from, to) {
-		return stmts
-	}
-
-	load := newLoad(location, from, to)
-
-	var all []build.Expr
-	added := false
-	for i, stmt := range stmts {
-		_, isComment := stmt.(*build.CommentBlock)
-		_, isString := stmt.(*build.StringExpr)
-		isDocString := isString && i == 0
-		if isComment || isDocString || added {
-			all = append(all, stmt)
-			continue
-		}
-		all = append(all, load)
-		all = append(all, stmt)
-		added = true
-	}
-	if !added { // Empty file or just comments.
-		all = append(all, load)
-	}
-	return all
+	return build.InsertLoad(stmts, location, from, to)
 }
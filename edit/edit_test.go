@@ -116,6 +116,105 @@ var splitOnSpacesTests = []struct {
 	{`  abc\ def `, []string{"abc def"}},
 }
 
+func TestAllLabels(t *testing.T) {
+	f, err := build.Parse("foo/bar/BUILD", []byte(`cc_library(
+    name = "lib",
+    srcs = ["lib.cc"],
+    data = [
+        "data/file.txt",
+        "//other/pkg",
+    ],
+    visibility = ["//visibility:public"],
+    deps = [
+        ":dep",
+        "//foo/bar:dep",
+        "@repo//baz:qux",
+        "@//main:dep",
+    ] + select({
+        ":opt": ["@other"],
+        "//conditions:default": [],
+    }),
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"//foo/bar:lib.cc",
+		"//foo/bar:data/file.txt",
+		"//other/pkg:pkg",
+		"//visibility:public",
+		"//foo/bar:dep",
+		"@repo//baz:qux",
+		"@//main:dep",
+		"@other//:other",
+	}
+	if got := AllLabels(f); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllLabels() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeLabels(t *testing.T) {
+	input := `cc_library(
+    name = "lib",
+    srcs = ["lib.cc"],
+    deps = [
+        ":dep",
+        "//other/pkg",
+        "@repo//:repo",
+        "@//other:dep",
+    ] + select({
+        ":opt": ["//foo/bar:opt_dep"],
+        "//conditions:default": [],
+    }),
+)
+`
+	absolute := `cc_library(
+    name = "lib",
+    srcs = ["lib.cc"],
+    deps = [
+        "//foo/bar:dep",
+        "//other/pkg:pkg",
+        "@repo//:repo",
+        "@//other:dep",
+    ] + select({
+        ":opt": ["//foo/bar:opt_dep"],
+        "//conditions:default": [],
+    }),
+)
+`
+	short := `cc_library(
+    name = "lib",
+    srcs = ["lib.cc"],
+    deps = [
+        ":dep",
+        "//other/pkg",
+        "@repo",
+        "@//other:dep",
+    ] + select({
+        ":opt": [":opt_dep"],
+        "//conditions:default": [],
+    }),
+)
+`
+	f, err := build.Parse("foo/bar/BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := NormalizeLabels(f, true); n != 2 {
+		t.Errorf("NormalizeLabels(absolute) changed %d labels, want 2", n)
+	}
+	if got := string(build.Format(f)); got != absolute {
+		t.Errorf("NormalizeLabels(absolute) = %s, want %s", got, absolute)
+	}
+	if n := NormalizeLabels(f, false); n != 4 {
+		t.Errorf("NormalizeLabels(short) changed %d labels, want 4", n)
+	}
+	if got := string(build.Format(f)); got != short {
+		t.Errorf("NormalizeLabels(short) = %s, want %s", got, short)
+	}
+}
+
 func TestSplitOnSpaces(t *testing.T) {
 	for i, tt := range splitOnSpacesTests {
 		result := SplitOnSpaces(tt.in)
@@ -153,6 +252,52 @@ load("other loc", "symbol")`,
 	}
 }
 
+func TestIndexOfRuleByName(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantIndex int
+	}{
+		{"foo", 1},
+		{"bar", 3},
+		{"baz", -1}, // nested in an if statement
+		{"qux", -1},
+	}
+
+	bld, err := build.Parse("BUILD", []byte(`load(":a.bzl", "a")
+
+cc_library(name = "foo")
+
+# A standalone comment block
+
+java_library(name = "bar")
+
+if True:
+    cc_library(name = "baz")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tst := range tests {
+		index, rule := IndexOfRuleByName(bld, tst.name)
+		if index != tst.wantIndex {
+			t.Errorf("IndexOfRuleByName(%q): got index %d, expected %d", tst.name, index, tst.wantIndex)
+		}
+		if index == -1 {
+			if rule != nil {
+				t.Errorf("IndexOfRuleByName(%q): got rule %q, expected nil", tst.name, rule.Name())
+			}
+			continue
+		}
+		if rule == nil || rule.Name() != tst.name {
+			t.Errorf("IndexOfRuleByName(%q): got wrong rule", tst.name)
+			continue
+		}
+		if bld.Stmt[index] != rule.Call {
+			t.Errorf("IndexOfRuleByName(%q): statement at index %d is not the rule", tst.name, index)
+		}
+	}
+}
+
 func TestAddValueToListAttribute(t *testing.T) {
 	tests := []struct{ input, expected string }{
 		{`rule(name="rule")`, `rule(name="rule", attr=["foo"])`},
@@ -255,6 +400,35 @@ func compareKeyValue(a, b build.Expr) bool {
 		aKeyVal.Value.(*build.StringExpr).Value == bKeyVal.Value.(*build.StringExpr).Value
 }
 
+func TestWrapInSelect(t *testing.T) {
+	f, err := build.ParseBuild("BUILD", []byte(`cc_library(
+    name = "lib",
+    copts = [
+        "-Wall",
+        "-O2",
+    ],
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	as := f.Rules("")[0].AttrDefn("copts")
+	as.RHS = WrapInSelect(as.RHS, "//conditions:default")
+	want := `cc_library(
+    name = "lib",
+    copts = select({
+        "//conditions:default": [
+            "-Wall",
+            "-O2",
+        ],
+    }),
+)
+`
+	if got := string(build.Format(f)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
 func TestDictionaryDelete(t *testing.T) {
 	tests := []struct {
 		input, expected string
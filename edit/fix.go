@@ -398,6 +398,55 @@ func cleanUnusedLoads(f *build.File) bool {
 	return fixed
 }
 
+// MergeLoads combines all load statements of the file that load from the same module
+// into the first of them, removing symbols that are loaded more than once.
+// It reports whether the file has been changed.
+func MergeLoads(f *build.File) bool {
+	fixed := false
+	first := make(map[string]*build.LoadStmt)
+	var all []build.Expr
+	for _, stmt := range f.Stmt {
+		load, ok := stmt.(*build.LoadStmt)
+		if !ok {
+			all = append(all, stmt)
+			continue
+		}
+		target, ok := first[load.Module.Value]
+		if !ok {
+			first[load.Module.Value] = load
+			all = append(all, load)
+			continue
+		}
+		fixed = true
+		for i := range load.To {
+			target.From = append(target.From, load.From[i])
+			target.To = append(target.To, load.To[i])
+		}
+		build.MoveComments(load, target)
+	}
+
+	// Remove duplicate symbols
+	for _, load := range first {
+		var fromSymbols, toSymbols []*build.Ident
+		seen := make(map[[2]string]bool)
+		for i := range load.To {
+			key := [2]string{load.To[i].Name, load.From[i].Name}
+			if seen[key] {
+				fixed = true
+				continue
+			}
+			seen[key] = true
+			fromSymbols = append(fromSymbols, load.From[i])
+			toSymbols = append(toSymbols, load.To[i])
+		}
+		load.From = fromSymbols
+		load.To = toSymbols
+	}
+
+	f.Stmt = all
+	return fixed
+}
+
 // movePackageDeclarationToTheTop ensures that the call to package() is done
 // before everything else (except comments).
 func movePackageDeclarationToTheTop(f *build.File) bool {
@@ -504,10 +553,17 @@ var FileLevelFixes = []struct {
 		"Prefer '+=' over 'extend' or 'append'"},
 	{"unusedLoads", cleanUnusedLoads,
 		"Remove unused symbols from load statements"},
+	{"mergeLoads", MergeLoads,
+		"Combine load statements that load from the same module"},
 	{"moveLicensesAndDistribs", moveLicensesAndDistribs,
 		"Move licenses and distribs to the package function"},
 }
 
+// WarningFixes maps linter warning categories to functions that apply their automatic fixes to
+// a whole file and report whether the file has been changed. It's populated by binaries that link
+// the warn package, which can't be imported here because it depends on this package.
+var WarningFixes = map[string]func(f *build.File, pkg string) bool{}
+
 // FixRule aims to fix errors in BUILD files, remove deprecated features, and
 // simplify the code.
 func FixRule(f *build.File, pkg string, rule *build.Rule, fixes []string) *build.File {
@@ -74,3 +74,48 @@ foo()`,
 		}
 	}
 }
+
+func TestMergeLoads(t *testing.T) {
+	tests := []struct {
+		input, expected string
+		shouldMerge     bool
+	}{
+		{`load(":a.bzl", "x")
+load(":b.bzl", "y")
+load(":a.bzl", "z", w = "x", x = "x")
+
+foo()`,
+			`load(":a.bzl", "x", "z", w = "x")
+load(":b.bzl", "y")
+
+foo()`,
+			true},
+		{`load(":a.bzl", "x")
+load(":b.bzl", "x")
+
+foo()`,
+			`load(":a.bzl", "x")
+load(":b.bzl", "x")
+
+foo()`,
+			false},
+	}
+
+	for _, tst := range tests {
+		bld, err := build.Parse("BUILD", []byte(tst.input))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if result := MergeLoads(bld); result != tst.shouldMerge {
+			t.Errorf("TestMergeLoads: expected %v, got %v", tst.shouldMerge, result)
+		}
+
+		got := strings.TrimSpace(string(build.Format(bld)))
+		want := strings.TrimSpace(tst.expected)
+
+		if got != want {
+			t.Errorf("TestMergeLoads: got:\n%s\nexpected:\n%s", got, want)
+		}
+	}
+}
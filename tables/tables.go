@@ -204,6 +204,182 @@ var NamePriority = map[string]int{
 	"//conditions:default": 50,
 }
 
+// RedundantDefaults maps "rule.attr" keys to the documented default value of the attribute,
+// written as it appears in a BUILD file. Setting an attribute to this value has no effect.
+// The table is deliberately conservative: only attributes whose default doesn't depend on
+// other attributes or on the Bazel version are listed.
+var RedundantDefaults = map[string]string{
+	"cc_library.alwayslink":         "False",
+	"cc_library.linkstatic":         "False",
+	"cc_test.flaky":                 "False",
+	"java_binary.create_executable": "True",
+	"java_library.neverlink":        "False",
+	"java_test.flaky":               "False",
+	"py_test.flaky":                 "False",
+	"sh_test.flaky":                 "False",
+}
+
+// SingularFileAttributes lists "rule.attr" keys of label attributes that expect exactly one file.
+var SingularFileAttributes = map[string]bool{
+	"alias.actual":             true,
+	"android_binary.manifest":  true,
+	"android_library.manifest": true,
+	"cc_binary.win_def_file":   true,
+	"cc_library.win_def_file":  true,
+	"java_import.srcjar":       true,
+	"py_binary.main":           true,
+	"py_test.main":             true,
+}
+
+// DeprecatedRepositoryRules maps names of deprecated repository rules used in WORKSPACE files
+// to instructions on how to migrate away from them.
+var DeprecatedRepositoryRules = map[string]string{
+	"maven_jar":        `Use "maven_install" from rules_jvm_external (https://github.com/bazelbuild/rules_jvm_external) instead.`,
+	"maven_server":     `Use the "repositories" attribute of "maven_install" from rules_jvm_external (https://github.com/bazelbuild/rules_jvm_external) instead.`,
+	"new_http_archive": `Use "http_archive" from @bazel_tools//tools/build_defs/repo:http.bzl with the "build_file" attribute instead.`,
+}
+
+// PluralAttributes maps singular attribute names that are common typos to their plural forms.
+var PluralAttributes = map[string]string{
+	"dep": "deps",
+	"out": "outs",
+	"src": "srcs",
+}
+
+// ExecAttributes maps rule kinds to the attributes that should be renamed to build
+// their dependencies for the execution platform (cfg = "exec") instead of the host.
+var ExecAttributes = map[string]map[string]string{
+	"genrule": {"tools": "exec_tools"},
+}
+
+// PluralAttributeRules maps rule kinds to the plural attributes (see PluralAttributes)
+// they support. The singular forms of these attributes don't exist for the rules.
+var PluralAttributeRules = map[string][]string{
+	"cc_binary":    {"deps", "srcs"},
+	"cc_library":   {"deps", "srcs"},
+	"cc_test":      {"deps", "srcs"},
+	"filegroup":    {"srcs"},
+	"genrule":      {"outs", "srcs"},
+	"java_binary":  {"deps", "srcs"},
+	"java_library": {"deps", "srcs"},
+	"java_test":    {"deps", "srcs"},
+	"py_binary":    {"deps", "srcs"},
+	"py_library":   {"deps", "srcs"},
+	"py_test":      {"deps", "srcs"},
+	"sh_binary":    {"deps", "srcs"},
+	"sh_library":   {"deps", "srcs"},
+	"sh_test":      {"deps", "srcs"},
+}
+
+// ReservedConfigSettingNames is a set of names that shouldn't be used for config_setting
+// rules, e.g. names of constraint values used in the repository. Empty by default.
+var ReservedConfigSettingNames = map[string]bool{}
+
+// RequiredTestTags is a list of tags at least one of which every test rule should have,
+// e.g. a test category required by a CI system. Empty by default.
+var RequiredTestTags = []string{}
+
+// RequiredAttributes maps rule kinds to the attributes that must be set for them.
+// It can be extended to enforce the contracts of custom rules and macros.
+var RequiredAttributes = map[string][]string{
+	"alias":       {"actual"},
+	"genquery":    {"expression", "scope"},
+	"genrule":     {"outs"},
+	"java_import": {"jars"},
+	"sh_binary":   {"srcs"},
+	"sh_test":     {"srcs"},
+}
+
+// RequiredLoads maps names of symbols that aren't built into Starlark to the
+// labels of the .bzl files they should be loaded from.
+var RequiredLoads = map[string]string{
+	"http_file": "@bazel_tools//tools/build_defs/repo:http.bzl",
+	"http_jar":  "@bazel_tools//tools/build_defs/repo:http.bzl",
+	"maybe":     "@bazel_tools//tools/build_defs/repo:utils.bzl",
+	"paths":     "@bazel_skylib//lib:paths.bzl",
+}
+
+// StarlarkBuiltins lists the names that are predeclared in .bzl files: the Starlark built-in
+// constants and functions and the global symbols provided by Bazel.
+var StarlarkBuiltins = map[string]bool{
+	// Starlark
+	"False":     true,
+	"None":      true,
+	"True":      true,
+	"abs":       true,
+	"all":       true,
+	"any":       true,
+	"bool":      true,
+	"bytes":     true,
+	"dict":      true,
+	"dir":       true,
+	"enumerate": true,
+	"fail":      true,
+	"float":     true,
+	"getattr":   true,
+	"hasattr":   true,
+	"hash":      true,
+	"int":       true,
+	"len":       true,
+	"list":      true,
+	"max":       true,
+	"min":       true,
+	"print":     true,
+	"range":     true,
+	"repr":      true,
+	"reversed":  true,
+	"set":       true,
+	"sorted":    true,
+	"str":       true,
+	"tuple":     true,
+	"type":      true,
+	"zip":       true,
+	// Bazel
+	"CcInfo":                   true,
+	"DefaultInfo":              true,
+	"InstrumentedFilesInfo":    true,
+	"JavaInfo":                 true,
+	"Label":                    true,
+	"OutputGroupInfo":          true,
+	"PyInfo":                   true,
+	"RunEnvironmentInfo":       true,
+	"analysis_test_transition": true,
+	"apple_common":             true,
+	"aspect":                   true,
+	"attr":                     true,
+	"cc_common":                true,
+	"config":                   true,
+	"config_common":            true,
+	"configuration_field":      true,
+	"coverage_common":          true,
+	"depset":                   true,
+	"exec_group":               true,
+	"java_common":              true,
+	"json":                     true,
+	"module_extension":         true,
+	"native":                   true,
+	"platform_common":          true,
+	"proto":                    true,
+	"provider":                 true,
+	"repository_rule":          true,
+	"rule":                     true,
+	"select":                   true,
+	"struct":                   true,
+	"subrule":                  true,
+	"tag_class":                true,
+	"testing":                  true,
+	"transition":               true,
+	"visibility":               true,
+}
+
+// NamingConventions maps rule kinds to regular expressions that the names of the rules of
+// these kinds must fully match, e.g. {"*_test": ".*_test"}. Kinds can be patterns with
+// wildcards as accepted by path.Match. Empty by default.
+var NamingConventions = map[string]string{}
+
+// TestNameSuffixes lists suffixes of target names that suggest the target is a test.
+var TestNameSuffixes = []string{"_test", "_unittest"}
+
 var StripLabelLeadingSlashes = false
 
 var ShortenAbsoluteLabelsToRelative = false
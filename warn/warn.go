@@ -2,6 +2,7 @@
 package warn
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
@@ -20,9 +21,24 @@ type LinterFinding struct {
 }
 
 // LinterReplacement is a low-level object returned by single fixer functions.
+// It replaces the node Old points to with New. Statements can't be inserted that way,
+// if OldStmts is set the list of statements it points to (e.g. &f.Stmt) is replaced
+// with NewStmts instead.
 type LinterReplacement struct {
 	Old *build.Expr
 	New build.Expr
+
+	OldStmts *[]build.Expr
+	NewStmts []build.Expr
+}
+
+// apply applies the replacement to the syntax tree.
+func (r LinterReplacement) apply() {
+	if r.OldStmts != nil {
+		*r.OldStmts = r.NewStmts
+		return
+	}
+	*r.Old = r.New
 }
 
 // A Finding is a warning reported by the analyzer. It may contain an optional suggested fix.
@@ -35,6 +51,9 @@ type Finding struct {
 	URL         string
 	Actionable  bool
 	Replacement *Replacement
+
+	// The automatic fix of the finding that can be applied with ApplyFix.
+	fix []LinterReplacement
 }
 
 // A Replacement is a suggested fix. Text between Start and End should be replaced with Content.
@@ -92,8 +111,71 @@ var RuleWarningMap = map[string]func(f *build.File, pkg string, expr build.Expr)
 
 // FileWarningMap lists the warnings that run on the whole file.
 var FileWarningMap = map[string]func(f *build.File) []*LinterFinding{
-	"attr-cfg":     attrConfigurationWarning,
-	"attr-license": attrLicenseWarning,
+	"alwayslink-no-srcs":         alwayslinkNoSrcsWarning,
+	"attr-cfg":                   attrConfigurationWarning,
+	"attr-license":               attrLicenseWarning,
+	"backslash-label":            backslashLabelWarning,
+	"binary-named-test":          binaryNamedTestWarning,
+	"conflicting-load-symbol":    conflictingLoadSymbolWarning,
+	"default-testonly":           defaultTestonlyWarning,
+	"deprecated-bind":            deprecatedBindWarning,
+	"deprecated-repository-rule": deprecatedRepositoryRuleWarning,
+	"duplicated-attr":            duplicatedAttrWarning,
+	"empty-string-in-list":       emptyStringInListWarning,
+	"empty-test-suite":           emptyTestSuiteWarning,
+	"exec-tools":                 execToolsWarning,
+	"exports-files-visibility":   exportsFilesNoVisibilityWarning,
+	"exports-not-in-deps":        exportsNotInDepsWarning,
+	"genrule-cmd-output-ref":     genruleCmdOutputRefWarning,
+	"glob-overlap":               globOverlapWarning,
+	"glob-singular-attr":         globInSingularAttrWarning,
+	"includes-escape":            includesEscapeWarning,
+	"java-binary-main-class":     javaBinaryMainClassWarning,
+	"main-repo-label":            mainRepoLabelWarning,
+	"missing-strip-prefix":       missingStripPrefixWarning,
+	"mixed-label-list":           mixedLabelListWarning,
+	"naming-convention":          namingConventionWarning,
+	"nested-select":              nestedSelectWarning,
+	"non-ascii-label":            nonASCIILabelWarning,
+	"non-literal-name":           nonLiteralNameWarning,
+	"private-symbol-load":        privateSymbolExportWarning,
+	"proto-library-srcs":         protoLibrarySrcsWarning,
+	"public-visibility-mix":      publicVisibilityMixWarning,
+	"redundant-concat":           redundantConcatWarning,
+	"redundant-default":          redundantDefaultWarning,
+	"redundant-package-group":    redundantPackageGroupWarning,
+	"repo-build-file-path":       repoBuildFilePathWarning,
+	"required-attr":              requiredAttrWarning,
+	"required-test-tag":          requiredTestTagWarning,
+	"reserved-config-setting":    reservedConfigSettingWarning,
+	"rule-in-control-flow":       ruleInControlFlowWarning,
+	"runfiles-glob":              runfilesGlobWarning,
+	"select-key":                 selectKeyWarning,
+	"sh-single-script":           shSingleScriptWarning,
+	"singular-attr-typo":         singularAttrTypoWarning,
+	"trailing-comma":             trailingCommaWarning,
+	"tuple-attr":                 tupleAttrWarning,
+	"unloaded-symbol":            usedUnloadedSymbolWarning,
+	"verbose-label":              verboseLabelWarning,
+	"visibility-not-list":        visibilityNotListWarning,
+}
+
+// PackageWarningMap lists the warnings that run on the whole file and need to know where
+// the file is located, see WarningContext.
+var PackageWarningMap = map[string]func(f *build.File, ctx *WarningContext) []*LinterFinding{
+	"cross-package-src":        crossPackageSrcWarning,
+	"exports-nonexistent-file": exportsNonexistentFileWarning,
+	"py-main-in-srcs":          pyMainInSrcsWarning,
+	"testonly-dep":             testonlyDepWarning,
+	"visibility-package-group": visibilityPackageGroupWarning,
+}
+
+// A WarningContext contains the information about a linted file that can't be derived from
+// its syntax tree. File.Path can't be used for that, it's the path the file has been read
+// from, which may as well be absolute or relative to the current directory.
+type WarningContext struct {
+	Pkg string // package of the file relative to the workspace root, e.g. "foo/bar"
+	Dir string // directory of the file on the filesystem, "" if unknown (e.g. for stdin)
 }
 
 // LegacyFileWarningMap lists the warnings that run on the whole file with legacy interface.
@@ -145,8 +227,28 @@ var LegacyFileWarningMap = map[string]func(f *build.File, fix bool) []*Finding{
 // nonDefaultWarnings contains warnings that are enabled by default because they're not applicable
 // for all files and cause too much diff noise when applied.
 var nonDefaultWarnings = map[string]bool{
-	"out-of-order-load":   true, // load statements should be sorted by their labels
-	"unsorted-dict-items": true, // dict items should be sorted
+	"binary-named-test":        true, // a heuristic based on the names of the targets
+	"conflicting-load-symbol":  true, // also reported by the "load" warning as a repeated load
+	"default-testonly":         true, // test-only packages are a valid way to organize test helpers
+	"empty-test-suite":         true, // including all tests of a package is a valid use case
+	"exec-tools":               true, // a migration that depends on the Bazel version
+	"exports-files-visibility": true, // the default visibility is often intended
+	"exports-nonexistent-file": true, // requires access to the package directory
+	"exports-not-in-deps":      true, // a convention rather than a requirement of the Java rules
+	"glob-overlap":             true, // duplicates are harmless, listing a file explicitly can be intended
+	"java-binary-main-class":   true, // a heuristic, the main class may also be set in the manifest
+	"main-repo-label":          true, // "@//" and "//" differ in BUILD files of external repositories
+	"missing-strip-prefix":     true, // a heuristic, the archive may be unpacked on purpose with its top-level directory
+	"mixed-label-list":         true, // bare target names are valid relative labels
+	"nested-select":            true, // nested selects are valid but hard to reason about
+	"non-literal-name":         true, // computed names are valid, e.g. for rules created in loops
+	"out-of-order-load":        true, // load statements should be sorted by their labels
+	"redundant-default":        true, // only a small set of well-known defaults is supported
+	"runfiles-glob":            true, // empty globs are valid unless Bazel is run with --incompatible_disallow_empty_glob
+	"trailing-comma":           true, // formatting with buildifier adds trailing commas anyway
+	"unsorted-dict-items":      true, // dict items should be sorted
+	"verbose-label":            true, // labels in BUILD files are shortened by the formatter
+	"visibility-package-group": true, // the package group may be defined by a macro
 }
 
 // DisabledWarning checks if the warning was disabled by a comment.
@@ -204,6 +306,12 @@ func DisabledWarning(f *build.File, findingLine int, warning string) bool {
 
 // FileWarnings returns a list of all warnings found in the file.
 func FileWarnings(f *build.File, pkg string, enabledWarnings []string, fix bool) []*Finding {
+	return FileWarningsWithContext(f, &WarningContext{Pkg: pkg}, enabledWarnings, fix)
+}
+
+// FileWarningsWithContext is like FileWarnings but also passes the location of the file on
+// the filesystem to the warnings that need it.
+func FileWarningsWithContext(f *build.File, ctx *WarningContext, enabledWarnings []string, fix bool) []*Finding {
 	findings := []*Finding{}
 
 	// Sort the warnings to make sure they're applied in the same determined order
@@ -214,6 +322,9 @@ func FileWarnings(f *build.File, pkg string, enabledWarnings []string, fix bool)
 	for _, warn := range warnings {
 		if fct, ok := FileWarningMap[warn]; ok {
 			findings = append(findings, runFileWarningsFunction(warn, f, fct, fix)...)
+		} else if fct, ok := PackageWarningMap[warn]; ok {
+			withContext := func(f *build.File) []*LinterFinding { return fct(f, ctx) }
+			findings = append(findings, runFileWarningsFunction(warn, f, withContext, fix)...)
 		} else if fct, ok := LegacyFileWarningMap[warn]; ok {
 			for _, w := range fct(f, fix) {
 				if !DisabledWarning(f, w.Start.Line, warn) {
@@ -221,7 +332,7 @@ func FileWarnings(f *build.File, pkg string, enabledWarnings []string, fix bool)
 				}
 			}
 		} else if fct, ok := RuleWarningMap[warn]; ok {
-			findings = append(findings, runRuleWarningsFunction(warn, pkg, f, fct)...)
+			findings = append(findings, runRuleWarningsFunction(warn, ctx.Pkg, f, fct)...)
 		} else {
 			log.Fatalf("unexpected warning %q", warn)
 		}
@@ -237,10 +348,12 @@ func runFileWarningsFunction(category string, f *build.File, fct func(f *build.F
 		if !DisabledWarning(f, w.Start.Line, category) {
 			if fix && len(w.Replacement) > 0 {
 				for _, r := range w.Replacement {
-					*r.Old = r.New
+					r.apply()
 				}
 			} else {
-				findings = append(findings, makeFinding(f, w.Start, w.End, category, w.Message, true, nil))
+				finding := makeFinding(f, w.Start, w.End, category, w.Message, true, nil)
+				finding.fix = w.Replacement
+				findings = append(findings, finding)
 			}
 		}
 	}
@@ -273,12 +386,85 @@ func FixWarnings(f *build.File, pkg string, enabledWarnings []string, verbose bo
 	}
 }
 
+// HasFix checks whether the finding has an automatic fix that can be applied with ApplyFix.
+// Only the warnings registered in FileWarningMap and PackageWarningMap provide fixes for
+// individual findings. The warnings from LegacyFileWarningMap (e.g. "native-build" or "load")
+// can only be fixed all at once with FixWarnings or RunCategories, their findings never have
+// a fix here.
+func (finding *Finding) HasFix() bool {
+	return len(finding.fix) > 0
+}
+
+// ApplyFix applies the automatic fix of a single finding to the file it has been found in,
+// other findings are not affected. Returns false if the finding has no automatic fix (see
+// HasFix for the findings of legacy warnings) or if it has been found in a different file.
+// Fixes can change the syntax tree, the findings should be recomputed after a fix has been
+// applied.
+func ApplyFix(f *build.File, finding *Finding) bool {
+	if finding.File != f || !finding.HasFix() {
+		return false
+	}
+	for _, r := range finding.fix {
+		r.apply()
+	}
+	finding.fix = nil
+	return true
+}
+
+// RunCategories runs only the given warning categories on the file, the functions of
+// all other warnings are not executed. Unknown and repeated categories are ignored.
+// pkg is the package of the file, as for FileWarnings. If fix is set, the automatic fixes
+// are applied and only the findings that couldn't be fixed are returned.
+func RunCategories(f *build.File, pkg string, categories []string, fix bool) []*Finding {
+	seen := make(map[string]bool)
+	var warnings []string
+	for _, category := range categories {
+		if seen[category] || !isWarningCategory(category) {
+			continue
+		}
+		seen[category] = true
+		warnings = append(warnings, category)
+	}
+	return FileWarnings(f, pkg, warnings, fix)
+}
+
+// isWarningCategory checks whether a warning category is registered in any of the warning maps.
+func isWarningCategory(category string) bool {
+	if _, ok := FileWarningMap[category]; ok {
+		return true
+	}
+	if _, ok := PackageWarningMap[category]; ok {
+		return true
+	}
+	if _, ok := LegacyFileWarningMap[category]; ok {
+		return true
+	}
+	_, ok := RuleWarningMap[category]
+	return ok
+}
+
+// RegisterBuildozerFixes makes the automatic fixes of all warnings available to the
+// buildozer `fix` command, e.g. `buildozer 'fix native-build,unused-variable' //pkg:__pkg__`.
+func RegisterBuildozerFixes() {
+	for _, category := range AllWarnings {
+		category := category
+		edit.WarningFixes[category] = func(f *build.File, pkg string) bool {
+			before := build.Format(f)
+			FixWarnings(f, pkg, []string{category}, false)
+			return !bytes.Equal(before, build.Format(f))
+		}
+	}
+}
+
 func collectAllWarnings() []string {
 	var result []string
 	// Collect list of all warnings.
 	for k := range FileWarningMap {
 		result = append(result, k)
 	}
+	for k := range PackageWarningMap {
+		result = append(result, k)
+	}
 	for k := range LegacyFileWarningMap {
 		result = append(result, k)
 	}
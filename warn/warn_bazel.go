@@ -4,10 +4,17 @@ package warn
 
 import (
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bazelbuild/buildtools/build"
 	"github.com/bazelbuild/buildtools/edit"
+	"github.com/bazelbuild/buildtools/tables"
 )
 
 func constantGlobWarning(f *build.File, fix bool) []*Finding {
@@ -154,16 +161,42 @@ func positionalArgumentsWarning(f *build.File, pkg string, stmt build.Expr) *Fin
 	if id, ok := call.X.(*build.Ident); !ok || functionsWithPositionalArguments[id.Name] {
 		return nil
 	}
-	for _, arg := range call.List {
-		if _, ok := arg.(*build.AssignExpr); ok {
-			continue
-		}
-		start, end := arg.Span()
+	if positional := call.Positional(); len(positional) > 0 {
+		start, end := positional[0].Span()
 		return makeFinding(f, start, end, "positional-args", msg, true, nil)
 	}
 	return nil
 }
 
+func crossPackageSrcWarning(f *build.File, ctx *WarningContext) []*LinterFinding {
+	if f.Type != build.TypeBuild || ctx.Pkg == "" {
+		// Absolute labels can't be checked if the package is unknown
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		srcs, ok := rule.Attr("srcs").(*build.ListExpr)
+		if !ok {
+			continue
+		}
+		for _, item := range srcs.List {
+			str, ok := item.(*build.StringExpr)
+			if !ok || !(strings.HasPrefix(str.Value, "//") || strings.HasPrefix(str.Value, "@")) {
+				// Relative labels always refer to the current package
+				continue
+			}
+			repo, labelPkg, _ := edit.ParseLabel(str.Value)
+			if repo == "" && labelPkg == ctx.Pkg {
+				continue
+			}
+			findings = append(findings, makeLinterFinding(str,
+				fmt.Sprintf(`The source %q belongs to another package. Consider using a "filegroup" in that package instead.`, str.Value)))
+		}
+	}
+	return findings
+}
+
 func argsKwargsInBuildFilesWarning(f *build.File, fix bool) []*Finding {
 	findings := []*Finding{}
 
@@ -197,3 +230,1640 @@ func argsKwargsInBuildFilesWarning(f *build.File, fix bool) []*Finding {
 	})
 	return findings
 }
+
+func redundantDefaultWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for i := range f.Stmt {
+		call, ok := f.Stmt[i].(*build.CallExpr)
+		if !ok {
+			continue
+		}
+		kind, ok := call.X.(*build.Ident)
+		if !ok {
+			continue
+		}
+
+		isRedundant := func(arg build.Expr) bool {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				return false
+			}
+			name, ok := as.LHS.(*build.Ident)
+			if !ok {
+				return false
+			}
+			value, ok := tables.RedundantDefaults[kind.Name+"."+name.Name]
+			return ok && build.FormatString(as.RHS) == value
+		}
+		fix := sharedListReplacement(&f.Stmt[i], func(arg build.Expr) bool { return !isRedundant(arg) })
+
+		for _, arg := range call.List {
+			if !isRedundant(arg) {
+				continue
+			}
+			as := arg.(*build.AssignExpr)
+			findings = append(findings,
+				makeLinterFinding(as, fmt.Sprintf(`The attribute %q of %q is set to its default value %s and can be removed.`,
+					as.LHS.(*build.Ident).Name, kind.Name, build.FormatString(as.RHS)), fix))
+		}
+	}
+	return findings
+}
+
+func exportsNonexistentFileWarning(f *build.File, ctx *WarningContext) []*LinterFinding {
+	if f.Type != build.TypeBuild || ctx.Dir == "" {
+		// The files can't be looked up
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("exports_files") {
+		if len(rule.Call.List) == 0 {
+			continue
+		}
+		list, ok := rule.Call.List[0].(*build.ListExpr)
+		if !ok {
+			continue
+		}
+		for _, item := range list.List {
+			str, ok := item.(*build.StringExpr)
+			if !ok {
+				continue
+			}
+			if strings.HasPrefix(str.Value, "@") || strings.HasPrefix(str.Value, "//") || strings.Contains(str.Value, ":") {
+				// Not a plain file name
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(ctx.Dir, filepath.FromSlash(str.Value))); !os.IsNotExist(err) {
+				continue
+			}
+			findings = append(findings,
+				makeLinterFinding(str, fmt.Sprintf(`The file %q exported by "exports_files" doesn't exist in the package.`, str.Value)))
+		}
+	}
+	return findings
+}
+
+func deprecatedBindWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeWorkspace {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, stmt := range f.Stmt {
+		call, ok := stmt.(*build.CallExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := call.X.(*build.Ident); !ok || ident.Name != "bind" {
+			continue
+		}
+		findings = append(findings,
+			makeLinterFinding(call, `The "bind" function is deprecated. Refer to the target directly `+
+				`or create an "alias" rule in a BUILD file and use its label instead.`))
+	}
+	return findings
+}
+
+func globInSingularAttrWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		for _, arg := range rule.Call.List {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				continue
+			}
+			name, ok := as.LHS.(*build.Ident)
+			if !ok || !tables.SingularFileAttributes[rule.Kind()+"."+name.Name] {
+				continue
+			}
+			if _, ok := isFunctionCall(as.RHS, "glob"); !ok {
+				continue
+			}
+			findings = append(findings,
+				makeLinterFinding(as.RHS, fmt.Sprintf(`The attribute %q of %q expects a single file, but "glob" can match any number of files.`,
+					name.Name, rule.Kind())))
+		}
+	}
+	return findings
+}
+
+// isZeroWidthRune reports whether r is an invisible character that is often pasted by accident.
+func isZeroWidthRune(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+		return true
+	}
+	return false
+}
+
+func deprecatedRepositoryRuleWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeWorkspace {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, stmt := range f.Stmt {
+		call, ok := stmt.(*build.CallExpr)
+		if !ok {
+			continue
+		}
+		ident, ok := call.X.(*build.Ident)
+		if !ok {
+			continue
+		}
+		migration, ok := tables.DeprecatedRepositoryRules[ident.Name]
+		if !ok {
+			continue
+		}
+		findings = append(findings,
+			makeLinterFinding(call, fmt.Sprintf(`The repository rule %q is deprecated. %s`, ident.Name, migration)))
+	}
+	return findings
+}
+
+func nonASCIILabelWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	check := func(expr *build.Expr, attr string) {
+		str, ok := (*expr).(*build.StringExpr)
+		if !ok {
+			return
+		}
+		for _, r := range str.Value {
+			if r < utf8.RuneSelf {
+				continue
+			}
+			msg := fmt.Sprintf(`The value %q of the attribute %q contains the non-ASCII character %U. `+
+				`Target names and labels should only contain ASCII characters.`, str.Value, attr, r)
+			stripped := strings.Map(func(r rune) rune {
+				if isZeroWidthRune(r) {
+					return -1
+				}
+				return r
+			}, str.Value)
+			if stripped == str.Value {
+				findings = append(findings, makeLinterFinding(str, msg))
+			} else {
+				// Only invisible characters can be removed safely
+				newStr := *str
+				newStr.Value = stripped
+				findings = append(findings, makeLinterFinding(str, msg, LinterReplacement{Old: expr, New: &newStr}))
+			}
+			return
+		}
+	}
+
+	walkLabelAttrs(f, true, check)
+	return findings
+}
+
+// walkLabelAttrs calls fn for each value of label attributes of the rules of the file,
+// including the elements of label lists. If withName is set, the "name" attribute
+// values are visited as well.
+func walkLabelAttrs(f *build.File, withName bool, fn func(expr *build.Expr, attr string)) {
+	walkLabelAttrDefns(f, withName, func(rule *build.Rule, attr string, as *build.AssignExpr) {
+		if list, ok := as.RHS.(*build.ListExpr); ok {
+			for i := range list.List {
+				fn(&list.List[i], attr)
+			}
+		} else {
+			fn(&as.RHS, attr)
+		}
+	})
+}
+
+// walkLabelAttrDefns is like walkLabelAttrs but calls fn once for the definition of
+// each label attribute, together with the rule it belongs to.
+func walkLabelAttrDefns(f *build.File, withName bool, fn func(rule *build.Rule, attr string, as *build.AssignExpr)) {
+	for _, rule := range f.Rules("") {
+		for _, arg := range rule.Call.List {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				continue
+			}
+			name, ok := as.LHS.(*build.Ident)
+			if !ok {
+				continue
+			}
+			if !(withName && name.Name == "name") && (!tables.IsLabelArg[name.Name] || tables.LabelBlacklist[rule.Kind()+"."+name.Name]) {
+				continue
+			}
+			fn(rule, name.Name, as)
+		}
+	}
+}
+
+// sharedListReplacement returns a replacement of *old, a call or a list, with a copy that
+// only has the arguments or elements for which keep returns true, followed by the added ones.
+// Warnings that report several findings for the same call or list attach the same replacement
+// to all of them, so that the fixes don't override each other: fixing any of the findings
+// fixes all of them.
+func sharedListReplacement(old *build.Expr, keep func(item build.Expr) bool, add ...build.Expr) LinterReplacement {
+	var items *[]build.Expr
+	var replacement build.Expr
+	switch x := (*old).(type) {
+	case *build.CallExpr:
+		newCall := *x
+		items, replacement = &newCall.List, &newCall
+	case *build.ListExpr:
+		newList := *x
+		items, replacement = &newList.List, &newList
+	default:
+		panic(fmt.Sprintf("sharedListReplacement: unexpected %T", x))
+	}
+	kept := []build.Expr{}
+	for _, item := range *items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	*items = append(kept, add...)
+	return LinterReplacement{Old: old, New: replacement}
+}
+
+// keepAll can be passed to sharedListReplacement to only add items.
+func keepAll(build.Expr) bool {
+	return true
+}
+
+func publicVisibilityMixWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		as := rule.AttrDefn("visibility")
+		if as == nil {
+			continue
+		}
+		list, ok := as.RHS.(*build.ListExpr)
+		if !ok {
+			continue
+		}
+		var public *build.StringExpr
+		for _, item := range list.List {
+			if str, ok := item.(*build.StringExpr); ok && str.Value == "//visibility:public" {
+				public = str
+				break
+			}
+		}
+		if public == nil {
+			continue
+		}
+
+		fix := sharedListReplacement(&as.RHS, func(item build.Expr) bool { return item == public })
+		for _, item := range list.List {
+			if item == public {
+				continue
+			}
+			findings = append(findings,
+				makeLinterFinding(item, fmt.Sprintf(`The visibility entry %s is redundant, "//visibility:public" already makes the target visible to all packages.`,
+					build.FormatString(item)), fix))
+		}
+	}
+	return findings
+}
+
+func emptyTestSuiteWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("test_suite") {
+		if rule.Attr("tags") != nil {
+			// The tests are selected by tags
+			continue
+		}
+		if tests := rule.Attr("tests"); tests != nil {
+			if list, ok := tests.(*build.ListExpr); !ok || len(list.List) > 0 {
+				continue
+			}
+		}
+		findings = append(findings,
+			makeLinterFinding(rule.Call, fmt.Sprintf(`The test suite %q doesn't list any tests, so it includes all tests of the package. `+
+				`List the tests explicitly or select them by "tags".`, rule.Name())))
+	}
+	return findings
+}
+
+func exportsNotInDepsWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for i := range f.Stmt {
+		call, ok := f.Stmt[i].(*build.CallExpr)
+		if !ok {
+			continue
+		}
+		rule := f.Rule(call)
+		if !strings.HasPrefix(rule.Kind(), "java_") {
+			continue
+		}
+		exports, ok := rule.Attr("exports").(*build.ListExpr)
+		if !ok {
+			continue
+		}
+
+		// Only literal lists can be checked reliably
+		depsAttr := rule.AttrDefn("deps")
+		var deps *build.ListExpr
+		if depsAttr != nil {
+			if deps, ok = depsAttr.RHS.(*build.ListExpr); !ok {
+				continue
+			}
+		}
+		depsLabels := make(map[string]bool)
+		if deps != nil {
+			for _, dep := range deps.List {
+				str, ok := dep.(*build.StringExpr)
+				if !ok {
+					continue
+				}
+				depsLabels[str.Value] = true
+			}
+		}
+
+		var missing []*build.StringExpr
+		for _, export := range exports.List {
+			str, ok := export.(*build.StringExpr)
+			if !ok || depsLabels[str.Value] {
+				continue
+			}
+			depsLabels[str.Value] = true
+			missing = append(missing, str)
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		var newDeps []build.Expr
+		for _, str := range missing {
+			newDeps = append(newDeps, build.NewString(str.Value))
+		}
+		var fix LinterReplacement
+		if depsAttr != nil {
+			fix = sharedListReplacement(&depsAttr.RHS, keepAll, newDeps...)
+		} else {
+			fix = sharedListReplacement(&f.Stmt[i], keepAll,
+				&build.AssignExpr{LHS: build.NewIdent("deps"), Op: "=", RHS: build.NewList(newDeps...)})
+		}
+
+		for _, str := range missing {
+			findings = append(findings,
+				makeLinterFinding(str, fmt.Sprintf(`The label %q is exported by %q but isn't listed in its "deps".`, str.Value, rule.Name()), fix))
+		}
+	}
+	return findings
+}
+
+func tupleAttrWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		for _, arg := range rule.Call.List {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				continue
+			}
+			name, ok := as.LHS.(*build.Ident)
+			if !ok || !edit.IsList(name.Name) {
+				continue
+			}
+			tuple, ok := as.RHS.(*build.TupleExpr)
+			if !ok || tuple.NoBrackets {
+				continue
+			}
+			list := &build.ListExpr{
+				Comments:       tuple.Comments,
+				Start:          tuple.Start,
+				List:           tuple.List,
+				End:            tuple.End,
+				ForceMultiLine: tuple.ForceMultiLine,
+			}
+			findings = append(findings,
+				makeLinterFinding(tuple, fmt.Sprintf(`The attribute %q of %q should be a list, not a tuple.`, name.Name, rule.Kind()),
+					LinterReplacement{Old: &as.RHS, New: list}))
+		}
+	}
+	return findings
+}
+
+func verboseLabelWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	check := func(expr *build.Expr, attr string) {
+		str, ok := (*expr).(*build.StringExpr)
+		if !ok || !strings.Contains(str.Value, "//") || !strings.Contains(str.Value, ":") {
+			return
+		}
+		_, pkg, name := edit.ParseLabel(str.Value)
+		if pkg == "" || path.Base(pkg) != name {
+			return
+		}
+		// The package is passed as "" to keep labels absolute, only the target name is omitted
+		short := edit.ShortenLabel(str.Value, "")
+		if short == str.Value {
+			return
+		}
+		newStr := *str
+		newStr.Value = short
+		newStr.Token = ""
+		findings = append(findings, makeLinterFinding(str,
+			fmt.Sprintf(`The label %q can be written in the canonical form %q.`, str.Value, short),
+			LinterReplacement{Old: expr, New: &newStr}))
+	}
+
+	walkLabelAttrs(f, false, check)
+	return findings
+}
+
+func ruleInControlFlowWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	build.WalkStatements(f, func(expr build.Expr, stack []build.Expr) {
+		call, ok := expr.(*build.CallExpr)
+		if !ok {
+			return
+		}
+		// Only calls that look like rules, i.e. `foo(name = ...)`, other calls such as
+		// `print(...)` or `x.append(...)` are fine.
+		rule := f.Rule(call)
+		if _, ok := call.X.(*build.Ident); !ok || rule.Attr("name") == nil {
+			return
+		}
+		for _, node := range stack {
+			switch node.(type) {
+			case *build.ForStmt, *build.IfStmt:
+				findings = append(findings, makeLinterFinding(call,
+					fmt.Sprintf(`The rule %q is defined inside a loop or a conditional statement. `+
+						`Move the logic to a macro in a .bzl file or declare the rules at the top level.`, rule.Kind())))
+				return
+			}
+		}
+	})
+	return findings
+}
+
+// packageSpecSubsumes checks whether the package_group spec `outer` (e.g. "//foo/...")
+// matches all packages matched by the spec `inner` (e.g. "//foo/bar" or "//foo/bar/...").
+func packageSpecSubsumes(outer, inner string) bool {
+	if !strings.HasSuffix(outer, "/...") {
+		return false
+	}
+	prefix := strings.TrimSuffix(outer, "...")
+	if !strings.HasSuffix(prefix, "//") {
+		prefix = strings.TrimSuffix(prefix, "/")
+	}
+	pkg := strings.TrimSuffix(inner, "/...")
+	if pkg == prefix {
+		return true
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return strings.HasPrefix(pkg, prefix)
+}
+
+func redundantPackageGroupWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("package_group") {
+		as := rule.AttrDefn("packages")
+		if as == nil {
+			continue
+		}
+		list, ok := as.RHS.(*build.ListExpr)
+		if !ok {
+			continue
+		}
+
+		// Negated specs are left alone, they neither subsume nor are subsumed by other specs.
+		specs := make([]string, len(list.List))
+		for i, item := range list.List {
+			if str, ok := item.(*build.StringExpr); ok && !strings.HasPrefix(str.Value, "-") {
+				specs[i] = str.Value
+			}
+		}
+
+		type redundancy struct {
+			item  build.Expr
+			outer string
+		}
+		var redundant []redundancy
+		isRedundant := make(map[build.Expr]bool)
+		for i, item := range list.List {
+			outer := ""
+			for j, spec := range specs {
+				if i == j || specs[i] == "" || spec == "" {
+					continue
+				}
+				// Of two identical specs the latter is redundant.
+				if spec == specs[i] && j > i {
+					continue
+				}
+				if spec == specs[i] || packageSpecSubsumes(spec, specs[i]) {
+					outer = spec
+					break
+				}
+			}
+			if outer == "" {
+				continue
+			}
+			redundant = append(redundant, redundancy{item, outer})
+			isRedundant[item] = true
+		}
+
+		fix := sharedListReplacement(&as.RHS, func(item build.Expr) bool { return !isRedundant[item] })
+		for _, r := range redundant {
+			findings = append(findings,
+				makeLinterFinding(r.item, fmt.Sprintf(`The package specification %s is redundant, it's already covered by %q.`,
+					build.FormatString(r.item), r.outer), fix))
+		}
+	}
+	return findings
+}
+
+func binaryNamedTestWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("cc_binary") {
+		name := rule.Name()
+		for _, suffix := range tables.TestNameSuffixes {
+			if !strings.HasSuffix(name, suffix) {
+				continue
+			}
+			findings = append(findings,
+				makeLinterFinding(rule.Call, fmt.Sprintf(`The binary %q is named like a test, consider using "cc_test" instead of "cc_binary".`, name)))
+			break
+		}
+	}
+	return findings
+}
+
+func repoBuildFilePathWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeWorkspace {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		if kind := rule.Kind(); kind != "new_local_repository" && kind != "new_git_repository" {
+			continue
+		}
+		str, ok := rule.Attr("build_file").(*build.StringExpr)
+		if !ok || strings.HasPrefix(str.Value, "//") || strings.HasPrefix(str.Value, "@") || strings.HasPrefix(str.Value, ":") {
+			// Labels are resolved within the workspace
+			continue
+		}
+		var problem string
+		if path.IsAbs(str.Value) {
+			problem = "is an absolute path"
+		} else if clean := path.Clean(str.Value); clean == ".." || strings.HasPrefix(clean, "../") {
+			problem = "points outside of the workspace"
+		} else {
+			continue
+		}
+		findings = append(findings,
+			makeLinterFinding(str, fmt.Sprintf(`The "build_file" of the repository %q %s. `+
+				`Put the BUILD file into the workspace and refer to it with a label instead.`, rule.Name(), problem)))
+	}
+	return findings
+}
+
+func backslashLabelWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	walkLabelAttrs(f, false, func(expr *build.Expr, attr string) {
+		str, ok := (*expr).(*build.StringExpr)
+		if !ok || !strings.Contains(str.Value, "\\") {
+			return
+		}
+		newStr := *str
+		newStr.Value = strings.Replace(str.Value, "\\", "/", -1)
+		findings = append(findings,
+			makeLinterFinding(str, fmt.Sprintf(`The label %q of the attribute %q contains backslashes. Labels should use forward slashes.`, str.Value, attr),
+				LinterReplacement{Old: expr, New: &newStr}))
+	})
+	return findings
+}
+
+// usesSingleOutputVariable checks whether a genrule command uses the "$@" make variable.
+// "$$" is an escaped dollar sign.
+func usesSingleOutputVariable(cmd string) bool {
+	for i := 0; i+1 < len(cmd); i++ {
+		if cmd[i] != '$' {
+			continue
+		}
+		if cmd[i+1] == '@' {
+			return true
+		}
+		if cmd[i+1] == '$' {
+			i++
+		}
+	}
+	return false
+}
+
+func genruleCmdOutputRefWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("genrule") {
+		outs, ok := rule.Attr("outs").(*build.ListExpr)
+		if !ok || len(outs.List) < 2 {
+			continue
+		}
+		for _, attr := range []string{"cmd", "cmd_bash"} {
+			cmd := rule.Attr(attr)
+			if cmd == nil {
+				continue
+			}
+			build.Walk(cmd, func(expr build.Expr, stack []build.Expr) {
+				str, ok := expr.(*build.StringExpr)
+				if !ok || !usesSingleOutputVariable(str.Value) {
+					return
+				}
+				findings = append(findings,
+					makeLinterFinding(str, fmt.Sprintf(`The "$@" variable in the %q attribute can only be used if the genrule %q has a single output. `+
+						`Use "$(OUTS)" or refer to the outputs with "$(location <output>)" instead.`, attr, rule.Name())))
+			})
+		}
+	}
+	return findings
+}
+
+func visibilityPackageGroupWarning(f *build.File, ctx *WarningContext) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	groups := make(map[string]bool)
+	for _, group := range f.Rules("package_group") {
+		groups[group.Name()] = true
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		attr := "visibility"
+		if rule.Kind() == "package" {
+			attr = "default_visibility"
+		}
+		visibility, ok := rule.Attr(attr).(*build.ListExpr)
+		if !ok {
+			continue
+		}
+		for _, item := range visibility.List {
+			str, ok := item.(*build.StringExpr)
+			if !ok || strings.HasPrefix(str.Value, "//visibility:") {
+				continue
+			}
+			repo, labelPkg, name := edit.ParseLabel(str.Value)
+			if name == "__pkg__" || name == "__subpackages__" {
+				continue
+			}
+			if repo != "" || (labelPkg != ctx.Pkg && strings.HasPrefix(str.Value, "//")) {
+				// Package groups defined in other packages can't be checked
+				continue
+			}
+			if groups[name] {
+				continue
+			}
+			findings = append(findings, makeLinterFinding(str,
+				fmt.Sprintf(`The visibility entry %q refers to a package group %q which isn't defined in this package.`, str.Value, name)))
+		}
+	}
+	return findings
+}
+
+func singularAttrTypoWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		plurals := make(map[string]bool)
+		for _, attr := range tables.PluralAttributeRules[rule.Kind()] {
+			plurals[attr] = true
+		}
+		for i, arg := range rule.Call.List {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				continue
+			}
+			name, ok := as.LHS.(*build.Ident)
+			if !ok {
+				continue
+			}
+			plural, ok := tables.PluralAttributes[name.Name]
+			if !ok || !plurals[plural] {
+				continue
+			}
+			if rule.Attr(plural) != nil {
+				findings = append(findings,
+					makeLinterFinding(as, fmt.Sprintf(`The attribute %q of the rule %q is probably a typo, it's ignored in favor of %q.`,
+						name.Name, rule.Name(), plural)))
+				continue
+			}
+			newAs := *as
+			newAs.LHS = &build.Ident{Name: plural}
+			findings = append(findings,
+				makeLinterFinding(as, fmt.Sprintf(`The attribute %q of the rule %q is probably a typo, use %q instead.`,
+					name.Name, rule.Name(), plural),
+					LinterReplacement{Old: &rule.Call.List[i], New: &newAs}))
+		}
+	}
+	return findings
+}
+
+func nestedSelectWarning(f *build.File) []*LinterFinding {
+	var findings []*LinterFinding
+	build.Walk(f, func(expr build.Expr, stack []build.Expr) {
+		if _, ok := isFunctionCall(expr, "select"); !ok {
+			return
+		}
+		for _, node := range stack {
+			if _, ok := isFunctionCall(node, "select"); ok {
+				findings = append(findings, makeLinterFinding(expr,
+					`A select() is nested inside another select(). Consider flattening the conditions, e.g. by using config_setting_group or selects.with_or.`))
+				return
+			}
+		}
+	})
+	return findings
+}
+
+func selectKeyWarning(f *build.File) []*LinterFinding {
+	var findings []*LinterFinding
+	build.Walk(f, func(expr build.Expr, stack []build.Expr) {
+		call, ok := isFunctionCall(expr, "select")
+		if !ok || len(call.List) == 0 {
+			return
+		}
+		dict, ok := call.List[0].(*build.DictExpr)
+		if !ok {
+			return
+		}
+		for _, item := range dict.List {
+			kv, ok := item.(*build.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			// Keys that aren't string literals (e.g. constants) can't be checked.
+			key, ok := kv.Key.(*build.StringExpr)
+			if !ok || key.Value == "//conditions:default" {
+				continue
+			}
+			if strings.HasPrefix(key.Value, "//") || strings.HasPrefix(key.Value, "@") || strings.HasPrefix(key.Value, ":") {
+				continue
+			}
+			findings = append(findings, makeLinterFinding(key,
+				fmt.Sprintf(`The select() key %q doesn't look like a label. Keys should be labels of config_setting targets starting with "//", "@" or ":".`, key.Value)))
+		}
+	})
+	return findings
+}
+
+// looksLikeLabel checks whether a string value of a label attribute is an explicit label
+// (contains "//", "@" or ":") or a file name with an extension.
+func looksLikeLabel(value string) bool {
+	return strings.Contains(value, "//") || strings.Contains(value, "@") ||
+		strings.Contains(value, ":") || path.Ext(value) != ""
+}
+
+func mixedLabelListWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	walkLabelAttrs(f, false, func(expr *build.Expr, attr string) {
+		str, ok := (*expr).(*build.StringExpr)
+		if !ok || looksLikeLabel(str.Value) {
+			return
+		}
+		findings = append(findings,
+			makeLinterFinding(str, fmt.Sprintf(`The value %q of the attribute %q doesn't look like a label. Labels should start with "//", "@" or ":".`, str.Value, attr)))
+	})
+	return findings
+}
+
+func pyMainInSrcsWarning(f *build.File, ctx *WarningContext) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		if kind := rule.Kind(); kind != "py_binary" && kind != "py_test" {
+			continue
+		}
+		main, ok := rule.Attr("main").(*build.StringExpr)
+		if !ok {
+			continue
+		}
+		msg := fmt.Sprintf(`The main file %q of the rule %q is not listed in its "srcs".`, main.Value, rule.Name())
+
+		srcs := rule.AttrDefn("srcs")
+		if srcs == nil {
+			findings = append(findings, makeLinterFinding(main, msg))
+			continue
+		}
+		// Globs, concatenations and variables can't be checked.
+		list, ok := srcs.RHS.(*build.ListExpr)
+		if !ok {
+			continue
+		}
+		found, allStrings := false, true
+		for _, item := range list.List {
+			str, ok := item.(*build.StringExpr)
+			if !ok {
+				allStrings = false
+				break
+			}
+			// The files may be referred to by labels, e.g. ":main.py" or "//pkg:main.py".
+			if edit.LabelsEqual(str.Value, main.Value, ctx.Pkg) {
+				found = true
+			}
+		}
+		if found || !allStrings {
+			continue
+		}
+
+		newList := *list
+		newList.List = append(append([]build.Expr{}, list.List...), &build.StringExpr{Value: main.Value})
+		findings = append(findings, makeLinterFinding(main, msg, LinterReplacement{Old: &srcs.RHS, New: &newList}))
+	}
+	return findings
+}
+
+func requiredAttrWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		for _, attr := range tables.RequiredAttributes[rule.Kind()] {
+			if rule.Attr(attr) != nil {
+				continue
+			}
+			findings = append(findings, makeLinterFinding(rule.Call,
+				fmt.Sprintf(`The rule %q of kind %q is missing the required attribute %q.`, rule.Name(), rule.Kind(), attr)))
+		}
+	}
+	return findings
+}
+
+func exportsFilesNoVisibilityWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, stmt := range f.Stmt {
+		call, ok := isFunctionCall(stmt, "exports_files")
+		if !ok {
+			continue
+		}
+		// The visibility can be passed as the second positional argument.
+		if len(call.List) > 1 {
+			if _, ok := call.List[1].(*build.AssignExpr); !ok {
+				continue
+			}
+		}
+		if f.Rule(call).Attr("visibility") != nil {
+			continue
+		}
+		findings = append(findings, makeLinterFinding(call,
+			`The visibility of the exported files is not set. The default depends on the Bazel version and flags, consider setting it explicitly.`))
+	}
+	return findings
+}
+
+func reservedConfigSettingWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild || len(tables.ReservedConfigSettingNames) == 0 {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("config_setting") {
+		name, ok := rule.Attr("name").(*build.StringExpr)
+		if !ok || !tables.ReservedConfigSettingNames[name.Value] {
+			continue
+		}
+		findings = append(findings, makeLinterFinding(name,
+			fmt.Sprintf(`The name %q of the config_setting is reserved, e.g. it's also used for a platform constraint. Consider renaming it.`, name.Value)))
+	}
+	return findings
+}
+
+func requiredTestTagWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild || len(tables.RequiredTestTags) == 0 {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		if !strings.HasSuffix(rule.Kind(), "_test") {
+			continue
+		}
+		var tags []string
+		if attr := rule.Attr("tags"); attr != nil {
+			if _, ok := attr.(*build.ListExpr); !ok {
+				// Can't check the tags if they're not a list literal.
+				continue
+			}
+			tags = rule.AttrStrings("tags")
+		}
+		found := false
+		for _, tag := range tags {
+			for _, required := range tables.RequiredTestTags {
+				if tag == required {
+					found = true
+				}
+			}
+		}
+		if found {
+			continue
+		}
+		findings = append(findings, makeLinterFinding(rule.Call,
+			fmt.Sprintf(`The test %q should have one of the following tags: %s.`, rule.Name(), strings.Join(tables.RequiredTestTags, ", "))))
+	}
+	return findings
+}
+
+func mainRepoLabelWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	walkLabelAttrs(f, false, func(expr *build.Expr, attr string) {
+		str, ok := (*expr).(*build.StringExpr)
+		if !ok || !strings.HasPrefix(str.Value, "@//") {
+			return
+		}
+		newStr := *str
+		newStr.Value = str.Value[1:]
+		findings = append(findings,
+			makeLinterFinding(str, fmt.Sprintf(`The label %q of the attribute %q refers to the main repository explicitly, use %q instead.`, str.Value, attr, newStr.Value),
+				LinterReplacement{Old: expr, New: &newStr}))
+	})
+	return findings
+}
+
+// concatenationTerms returns the operands of a (possibly nested) list concatenation.
+func concatenationTerms(expr build.Expr) []build.Expr {
+	if bin, ok := expr.(*build.BinaryExpr); ok && bin.Op == "+" {
+		return append(concatenationTerms(bin.X), concatenationTerms(bin.Y)...)
+	}
+	return []build.Expr{expr}
+}
+
+// globPatternMatches checks whether a file name matches a glob pattern, e.g. "**/*.cc".
+func globPatternMatches(pattern, name string) bool {
+	segments := strings.Split(pattern, "/")
+	re := "^"
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		if segment == "**" {
+			if last {
+				re += ".*"
+			} else {
+				re += "(?:[^/]*/)*"
+			}
+			continue
+		}
+		parts := strings.Split(segment, "*")
+		for j := range parts {
+			parts[j] = regexp.QuoteMeta(parts[j])
+		}
+		re += strings.Join(parts, "[^/]*")
+		if !last {
+			re += "/"
+		}
+	}
+	matched, err := regexp.MatchString(re+"$", name)
+	return err == nil && matched
+}
+
+// globPatterns returns the string values of an argument of a glob() call, the argument
+// can be passed either positionally or as a keyword.
+func globPatterns(call *build.CallExpr, position int, keyword string) []string {
+	var arg build.Expr
+	for i, expr := range call.List {
+		if as, ok := expr.(*build.AssignExpr); ok {
+			if ident, ok := as.LHS.(*build.Ident); ok && ident.Name == keyword {
+				arg = as.RHS
+			}
+		} else if i == position {
+			arg = expr
+		}
+	}
+	list, ok := arg.(*build.ListExpr)
+	if !ok {
+		return nil
+	}
+	var patterns []string
+	for _, item := range list.List {
+		if str, ok := item.(*build.StringExpr); ok {
+			patterns = append(patterns, str.Value)
+		}
+	}
+	return patterns
+}
+
+func globOverlapWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		srcs := rule.Attr("srcs")
+		if srcs == nil {
+			continue
+		}
+		terms := concatenationTerms(srcs)
+		var includes, excludes []string
+		for _, term := range terms {
+			if call, ok := isFunctionCall(term, "glob"); ok {
+				includes = append(includes, globPatterns(call, 0, "include")...)
+				excludes = append(excludes, globPatterns(call, 1, "exclude")...)
+			}
+		}
+		if len(includes) == 0 {
+			continue
+		}
+		matchesAny := func(patterns []string, name string) bool {
+			for _, pattern := range patterns {
+				if globPatternMatches(pattern, name) {
+					return true
+				}
+			}
+			return false
+		}
+		for _, term := range terms {
+			list, ok := term.(*build.ListExpr)
+			if !ok {
+				continue
+			}
+			for _, item := range list.List {
+				str, ok := item.(*build.StringExpr)
+				if !ok || strings.HasPrefix(str.Value, "//") || strings.HasPrefix(str.Value, "@") {
+					continue
+				}
+				name := strings.TrimPrefix(str.Value, ":")
+				if !matchesAny(includes, name) || matchesAny(excludes, name) {
+					continue
+				}
+				findings = append(findings, makeLinterFinding(str,
+					fmt.Sprintf(`The source %q of the rule %q is also matched by a glob() in the same attribute.`, str.Value, rule.Name())))
+			}
+		}
+	}
+	return findings
+}
+
+// isTestonlyRule checks whether a rule can only be used by tests and other testonly rules.
+func isTestonlyRule(rule *build.Rule) bool {
+	switch rule.AttrLiteral("testonly") {
+	case "True", "1":
+		return true
+	}
+	return strings.HasSuffix(rule.Kind(), "_test") || rule.Kind() == "test_suite"
+}
+
+func testonlyDepWarning(f *build.File, ctx *WarningContext) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	testonly := make(map[string]bool)
+	for _, rule := range f.Rules("") {
+		if name := rule.Name(); name != "" && isTestonlyRule(rule) {
+			testonly[name] = true
+		}
+	}
+	if len(testonly) == 0 {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		deps := rule.Attr("deps")
+		if deps == nil || isTestonlyRule(rule) {
+			continue
+		}
+		build.Walk(deps, func(expr build.Expr, stack []build.Expr) {
+			str, ok := expr.(*build.StringExpr)
+			if !ok {
+				return
+			}
+			repo, labelPkg, name := edit.ParseLabel(str.Value)
+			samePkg := labelPkg == ""
+			if strings.HasPrefix(str.Value, "//") {
+				samePkg = labelPkg == ctx.Pkg
+			}
+			if repo != "" || !samePkg {
+				// Targets from other packages can't be checked.
+				return
+			}
+			if testonly[name] {
+				findings = append(findings, makeLinterFinding(str,
+					fmt.Sprintf(`The rule %q depends on the testonly target %q but isn't testonly itself.`, rule.Name(), str.Value)))
+			}
+		})
+	}
+	return findings
+}
+
+// gitHubArchiveURL matches the URLs of source archives generated by GitHub, their contents are
+// always located in a top-level directory named after the repository and the revision.
+var gitHubArchiveURL = regexp.MustCompile(`^https?://github\.com/[^/]+/[^/]+/archive/`)
+
+func missingStripPrefixWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeWorkspace {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("http_archive") {
+		if rule.Attr("strip_prefix") != nil {
+			continue
+		}
+		urls := rule.AttrStrings("urls")
+		if url := rule.AttrString("url"); url != "" {
+			urls = append(urls, url)
+		}
+		for _, url := range urls {
+			if gitHubArchiveURL.MatchString(url) {
+				findings = append(findings, makeLinterFinding(rule.Call,
+					fmt.Sprintf(`The http_archive %q downloads a GitHub source archive but has no "strip_prefix". `+
+						`The contents of such archives are located in a top-level directory.`, rule.Name())))
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func defaultTestonlyWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("package") {
+		attr := rule.AttrDefn("default_testonly")
+		if attr == nil {
+			continue
+		}
+		switch rule.AttrLiteral("default_testonly") {
+		case "True", "1":
+			findings = append(findings, makeLinterFinding(attr,
+				`"default_testonly = True" makes all targets of the package testonly, they can't be used by non-test targets from other packages. `+
+					`Consider setting "testonly = True" on the test-only targets individually or moving them to a separate package.`))
+		}
+	}
+	return findings
+}
+
+func shSingleScriptWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		if kind := rule.Kind(); kind != "sh_binary" && kind != "sh_test" {
+			continue
+		}
+		srcs := rule.AttrDefn("srcs")
+		if srcs == nil {
+			continue
+		}
+		// Only literal lists can be checked, the contents of globs are unknown.
+		list, ok := srcs.RHS.(*build.ListExpr)
+		if !ok || len(list.List) <= 1 {
+			continue
+		}
+		findings = append(findings, makeLinterFinding(srcs,
+			fmt.Sprintf(`The rule %q of kind %q should have a single script in "srcs", found %d. `+
+				`Move the other files to "data" or to a "sh_library" in "deps".`, rule.Name(), rule.Kind(), len(list.List))))
+	}
+	return findings
+}
+
+func nonLiteralNameWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		name := rule.Attr("name")
+		if name == nil {
+			continue
+		}
+		if _, ok := name.(*build.StringExpr); ok {
+			continue
+		}
+		findings = append(findings, makeLinterFinding(name,
+			fmt.Sprintf(`The name of the %q rule is not a string literal, tools that index targets by their names can't find it.`, rule.Kind())))
+	}
+	return findings
+}
+
+func includesEscapeWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		if !strings.HasPrefix(rule.Kind(), "cc_") {
+			continue
+		}
+		includes, ok := rule.Attr("includes").(*build.ListExpr)
+		if !ok {
+			continue
+		}
+		for _, expr := range includes.List {
+			str, ok := expr.(*build.StringExpr)
+			if !ok {
+				continue
+			}
+			var problem string
+			switch clean := path.Clean(str.Value); {
+			case strings.HasPrefix(clean, "/"):
+				problem = "is an absolute path"
+			case clean == ".." || strings.HasPrefix(clean, "../"):
+				problem = "points outside of the package"
+			default:
+				continue
+			}
+			findings = append(findings, makeLinterFinding(str,
+				fmt.Sprintf(`The include directory %q of the rule %q %s, include paths should be relative to the package.`, str.Value, rule.Name(), problem)))
+		}
+	}
+	return findings
+}
+
+func emptyStringInListWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	isEmptyString := func(item build.Expr) bool {
+		str, ok := item.(*build.StringExpr)
+		return ok && str.Value == ""
+	}
+	walkLabelAttrDefns(f, false, func(rule *build.Rule, attr string, as *build.AssignExpr) {
+		// Lists can also be parts of concatenations or selects.
+		build.WalkPointers(as, func(expr *build.Expr, stack []build.Expr) {
+			list, ok := (*expr).(*build.ListExpr)
+			if !ok {
+				return
+			}
+			fix := sharedListReplacement(expr, func(item build.Expr) bool { return !isEmptyString(item) })
+			for _, item := range list.List {
+				if isEmptyString(item) {
+					findings = append(findings, makeLinterFinding(item,
+						fmt.Sprintf(`The attribute %q of the rule %q contains an empty string.`, attr, rule.Name()), fix))
+				}
+			}
+		})
+	})
+	return findings
+}
+
+func javaBinaryMainClassWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("java_binary") {
+		// The main class may come from a library in "runtime_deps", and a java_binary
+		// without a launcher doesn't need a main class at all.
+		if rule.Attr("main_class") != nil || rule.Attr("runtime_deps") != nil || rule.Attr("create_executable") != nil {
+			continue
+		}
+		// Only literal lists can be checked, the contents of globs are unknown.
+		srcs, ok := rule.Attr("srcs").(*build.ListExpr)
+		if !ok || len(srcs.List) <= 1 {
+			continue
+		}
+		// Bazel uses the class with the same name as the rule if it's among the sources.
+		hasNamedSrc := false
+		for _, src := range srcs.List {
+			if str, ok := src.(*build.StringExpr); ok && path.Base(str.Value) == rule.Name()+".java" {
+				hasNamedSrc = true
+				break
+			}
+		}
+		if hasNamedSrc {
+			continue
+		}
+		findings = append(findings, makeLinterFinding(rule.Call,
+			fmt.Sprintf(`The java_binary %q has %d sources and no "main_class", its entry point can't be determined. `+
+				`Set "main_class" explicitly.`, rule.Name(), len(srcs.List))))
+	}
+	return findings
+}
+
+func visibilityNotListWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		attr := "visibility"
+		if rule.Kind() == "package" {
+			attr = "default_visibility"
+		}
+		as := rule.AttrDefn(attr)
+		if as == nil {
+			continue
+		}
+		str, ok := as.RHS.(*build.StringExpr)
+		if !ok {
+			continue
+		}
+		findings = append(findings, makeLinterFinding(str,
+			fmt.Sprintf(`The attribute %q of %q should be a list, not a string.`, attr, rule.Kind()),
+			LinterReplacement{Old: &as.RHS, New: &build.ListExpr{List: []build.Expr{str}}}))
+	}
+	return findings
+}
+
+func execToolsWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		renames := tables.ExecAttributes[rule.Kind()]
+		if len(renames) == 0 {
+			continue
+		}
+		for i, arg := range rule.Call.List {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				continue
+			}
+			name, ok := as.LHS.(*build.Ident)
+			if !ok {
+				continue
+			}
+			newName, ok := renames[name.Name]
+			if !ok {
+				continue
+			}
+			if rule.Attr(newName) != nil {
+				findings = append(findings,
+					makeLinterFinding(as, fmt.Sprintf(`The attribute %q of the rule %q builds its dependencies for the host, move them to %q.`,
+						name.Name, rule.Name(), newName)))
+				continue
+			}
+			newAs := *as
+			newAs.LHS = &build.Ident{Name: newName}
+			findings = append(findings,
+				makeLinterFinding(as, fmt.Sprintf(`The attribute %q of the rule %q builds its dependencies for the host, use %q instead to build them for the execution platform.`,
+					name.Name, rule.Name(), newName),
+					LinterReplacement{Old: &rule.Call.List[i], New: &newAs}))
+		}
+	}
+	return findings
+}
+
+func alwayslinkNoSrcsWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("cc_library") {
+		attr := rule.AttrDefn("alwayslink")
+		if attr == nil {
+			continue
+		}
+		if value := rule.AttrLiteral("alwayslink"); value != "True" && value != "1" {
+			continue
+		}
+		// Sources computed with glob() or other expressions may be non-empty.
+		if srcs := rule.Attr("srcs"); srcs != nil {
+			if list, ok := srcs.(*build.ListExpr); !ok || len(list.List) > 0 {
+				continue
+			}
+		}
+		findings = append(findings, makeLinterFinding(attr,
+			fmt.Sprintf(`The rule %q has no "srcs", "alwayslink" has no effect on a library without object files.`, rule.Name())))
+	}
+	return findings
+}
+
+func namingConventionWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild || len(tables.NamingConventions) == 0 {
+		return nil
+	}
+
+	// Iterate over the conventions in a fixed order to report the findings deterministically.
+	var kinds []string
+	for kind := range tables.NamingConventions {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	regexps := make(map[string]*regexp.Regexp)
+	for _, kind := range kinds {
+		re, err := regexp.Compile("^(?:" + tables.NamingConventions[kind] + ")$")
+		if err != nil {
+			// Invalid conventions can't be enforced.
+			continue
+		}
+		regexps[kind] = re
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		name, ok := rule.Attr("name").(*build.StringExpr)
+		if !ok {
+			continue
+		}
+		for _, kind := range kinds {
+			re := regexps[kind]
+			if re == nil {
+				continue
+			}
+			if matched, _ := path.Match(kind, rule.Kind()); !matched || re.MatchString(name.Value) {
+				continue
+			}
+			findings = append(findings, makeLinterFinding(name,
+				fmt.Sprintf(`The name %q of the %q rule doesn't match the naming convention %q.`, name.Value, rule.Kind(), tables.NamingConventions[kind])))
+		}
+	}
+	return findings
+}
+
+func duplicatedAttrWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for i := range f.Stmt {
+		call, ok := f.Stmt[i].(*build.CallExpr)
+		if !ok {
+			continue
+		}
+		kind, ok := call.X.(*build.Ident)
+		if !ok {
+			continue
+		}
+
+		last := make(map[string]*build.AssignExpr)
+		for _, arg := range call.List {
+			if as, ok := arg.(*build.AssignExpr); ok {
+				if name, ok := as.LHS.(*build.Ident); ok {
+					last[name.Name] = as
+				}
+			}
+		}
+
+		// Only the last occurrence of each attribute is kept.
+		fix := sharedListReplacement(&f.Stmt[i], func(arg build.Expr) bool {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				return true
+			}
+			name, ok := as.LHS.(*build.Ident)
+			return !ok || last[name.Name] == as
+		})
+		seen := make(map[string]bool)
+		for _, arg := range call.List {
+			as, ok := arg.(*build.AssignExpr)
+			if !ok {
+				continue
+			}
+			name, ok := as.LHS.(*build.Ident)
+			if !ok {
+				continue
+			}
+			if seen[name.Name] {
+				findings = append(findings,
+					makeLinterFinding(as, fmt.Sprintf(`The attribute %q of %q is set more than once, which is an error. Keep a single value.`,
+						name.Name, kind.Name), fix))
+			}
+			seen[name.Name] = true
+		}
+	}
+	return findings
+}
+
+func protoLibrarySrcsWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("proto_library") {
+		// Only literal lists can be checked, the contents of globs are unknown.
+		srcs, ok := rule.Attr("srcs").(*build.ListExpr)
+		if !ok {
+			continue
+		}
+		for _, item := range srcs.List {
+			str, ok := item.(*build.StringExpr)
+			if !ok {
+				continue
+			}
+			// Labels without an extension may refer to targets producing .proto files.
+			if ext := path.Ext(str.Value); ext == "" || ext == ".proto" {
+				continue
+			}
+			findings = append(findings, makeLinterFinding(str,
+				fmt.Sprintf(`The source %q of the proto_library %q is not a .proto file.`, str.Value, rule.Name())))
+		}
+	}
+	return findings
+}
+
+func runfilesGlobWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBuild {
+		return nil
+	}
+
+	findings := []*LinterFinding{}
+	for _, rule := range f.Rules("") {
+		data := rule.Attr("data")
+		if data == nil {
+			continue
+		}
+		// Globs can also be parts of concatenations or selects.
+		build.Walk(data, func(expr build.Expr, stack []build.Expr) {
+			call, ok := isFunctionCall(expr, "glob")
+			if !ok || call.Attr("allow_empty") != nil {
+				return
+			}
+			include := call.Attr("include")
+			if positional := call.Positional(); include == nil && len(positional) > 0 {
+				include = positional[0]
+			}
+			recursive := false
+			for _, pattern := range build.Strings(include) {
+				if strings.Contains(pattern, "**") {
+					recursive = true
+					break
+				}
+			}
+			if !recursive {
+				return
+			}
+			findings = append(findings, makeLinterFinding(call,
+				fmt.Sprintf(`The recursive glob in the "data" attribute of the rule %q doesn't set "allow_empty", `+
+					`set it explicitly to state whether an empty set of runfiles is expected.`, rule.Name())))
+		})
+	}
+	return findings
+}
@@ -285,7 +285,7 @@ func attrConfigurationWarning(f *build.File) []*LinterFinding {
 
 		findings = append(findings,
 			makeLinterFinding(param, `cfg = "data" for attr definitions has no effect and should be removed.`,
-				LinterReplacement{expr, &newCall}))
+				LinterReplacement{Old: expr, New: &newCall}))
 	})
 	return findings
 }
@@ -544,6 +544,63 @@ func nativeHTTPArchiveWarning(f *build.File, fix bool) []*Finding {
 	return notLoadedFunctionUsageCheck(f, "http-archive", []string{"http_archive"}, "@bazel_tools//tools/build_defs/repo:http.bzl", fix)
 }
 
+func usedUnloadedSymbolWarning(f *build.File) []*LinterFinding {
+	if f.Type != build.TypeBzl {
+		return nil
+	}
+
+	var unloaded []*build.Ident
+	toLoad := make(map[string]map[string]bool) // load labels to symbols
+	for _, ident := range f.FreeIdents() {
+		loadFrom, ok := tables.RequiredLoads[ident.Name]
+		if !ok {
+			continue
+		}
+		if toLoad[loadFrom] == nil {
+			toLoad[loadFrom] = make(map[string]bool)
+		}
+		toLoad[loadFrom][ident.Name] = true
+		unloaded = append(unloaded, ident)
+	}
+	if len(unloaded) == 0 {
+		return nil
+	}
+
+	// All findings share the same fix that loads all missing symbols. InsertLoad appends
+	// the symbols to the existing load statements, so they are copied first.
+	stmts := append([]build.Expr{}, f.Stmt...)
+	for i, stmt := range stmts {
+		if load, ok := stmt.(*build.LoadStmt); ok && toLoad[load.Module.Value] != nil {
+			newLoad := *load
+			newLoad.From = append([]*build.Ident{}, load.From...)
+			newLoad.To = append([]*build.Ident{}, load.To...)
+			stmts[i] = &newLoad
+		}
+	}
+	labels := []string{}
+	for label := range toLoad {
+		labels = append(labels, label)
+	}
+	// Each load is inserted before the previous ones, so iterate in reverse order
+	sort.Sort(sort.Reverse(sort.StringSlice(labels)))
+	for _, label := range labels {
+		symbols := []string{}
+		for symbol := range toLoad[label] {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+		stmts = edit.InsertLoad(stmts, label, symbols, symbols)
+	}
+	fix := LinterReplacement{OldStmts: &f.Stmt, NewStmts: stmts}
+
+	findings := []*LinterFinding{}
+	for _, ident := range unloaded {
+		findings = append(findings, makeLinterFinding(ident,
+			fmt.Sprintf(`Symbol "%s" is used but not loaded, it should be loaded from "%s".`, ident.Name, tables.RequiredLoads[ident.Name]), fix))
+	}
+	return findings
+}
+
 func nativeAndroidRulesWarning(f *build.File, fix bool) []*Finding {
 	if f.Type != build.TypeBzl && f.Type != build.TypeBuild {
 		return []*Finding{}
@@ -2,6 +2,7 @@ package warn
 
 import (
 	"fmt"
+	"github.com/bazelbuild/buildtools/build"
 	"github.com/bazelbuild/buildtools/tables"
 	"testing"
 )
@@ -493,3 +494,96 @@ android_binary()
 		},
 		scopeBzl|scopeBuild)
 }
+
+func TestUsedUnloadedSymbolWarning(t *testing.T) {
+	checkFindings(t, "unloaded-symbol", `
+load("@bazel_tools//tools/build_defs/repo:utils.bzl", "maybe")
+load("@bazel_skylib//lib:paths.bzl", "paths")
+
+def macro(http_file):
+    maybe(foo, bar)
+    http_file(name = paths.join("a", "b"))
+`,
+		[]string{},
+		scopeBzl)
+
+	checkFindingsAndFix(t, "unloaded-symbol", `
+"""My file"""
+
+def macro():
+    maybe(http_file, name = "foo")
+    http_jar(name = paths.basename(foo))
+    maybe(http_jar, name = "bar")
+`, `
+"""My file"""
+
+load("@bazel_skylib//lib:paths.bzl", "paths")
+load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_file", "http_jar")
+load("@bazel_tools//tools/build_defs/repo:utils.bzl", "maybe")
+
+def macro():
+    maybe(http_file, name = "foo")
+    http_jar(name = paths.basename(foo))
+    maybe(http_jar, name = "bar")
+`,
+		[]string{
+			`:4: Symbol "maybe" is used but not loaded, it should be loaded from "@bazel_tools//tools/build_defs/repo:utils.bzl".`,
+			`:4: Symbol "http_file" is used but not loaded, it should be loaded from "@bazel_tools//tools/build_defs/repo:http.bzl".`,
+			`:5: Symbol "http_jar" is used but not loaded, it should be loaded from "@bazel_tools//tools/build_defs/repo:http.bzl".`,
+			`:5: Symbol "paths" is used but not loaded, it should be loaded from "@bazel_skylib//lib:paths.bzl".`,
+			`:6: Symbol "maybe" is used but not loaded, it should be loaded from "@bazel_tools//tools/build_defs/repo:utils.bzl".`,
+			`:6: Symbol "http_jar" is used but not loaded, it should be loaded from "@bazel_tools//tools/build_defs/repo:http.bzl".`,
+		},
+		scopeBzl)
+
+	checkFindingsAndFix(t, "unloaded-symbol", `
+load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")
+
+def macro():
+    http_archive(name = "foo")
+    http_file(name = "bar")
+`, `
+load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive", "http_file")
+
+def macro():
+    http_archive(name = "foo")
+    http_file(name = "bar")
+`,
+		[]string{
+			`:5: Symbol "http_file" is used but not loaded, it should be loaded from "@bazel_tools//tools/build_defs/repo:http.bzl".`,
+		},
+		scopeBzl)
+}
+
+func TestUsedUnloadedSymbolApplyFix(t *testing.T) {
+	f, err := build.ParseBzl("package/test_file.bzl", []byte(`load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")
+
+def macro():
+    http_file(name = "foo")
+    maybe(http_jar, name = "bar")
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := FileWarnings(f, "package", []string{"unloaded-symbol"}, false)
+	if len(findings) != 3 {
+		t.Fatalf("got %d findings, want 3", len(findings))
+	}
+	if !findings[0].HasFix() || !ApplyFix(f, findings[0]) {
+		t.Fatal("ApplyFix() didn't apply the fix of an unloaded-symbol finding")
+	}
+	// The fix is shared by all findings, it loads all missing symbols.
+	want := `load("@bazel_tools//tools/build_defs/repo:utils.bzl", "maybe")
+load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive", "http_file", "http_jar")
+
+def macro():
+    http_file(name = "foo")
+    maybe(http_jar, name = "bar")
+`
+	if got := string(build.Format(f)); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if findings := FileWarnings(f, "package", []string{"unloaded-symbol"}, false); len(findings) != 0 {
+		t.Errorf("got %d findings after the fix, want 0", len(findings))
+	}
+}
@@ -1,6 +1,14 @@
 package warn
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazelbuild/buildtools/build"
+	"github.com/bazelbuild/buildtools/tables"
+)
 
 func TestConstantGlob(t *testing.T) {
 	checkFindings(t, "constant-glob", `
@@ -84,6 +92,60 @@ register_toolchains(
 		scopeBuild|scopeWorkspace)
 }
 
+func TestCrossPackageSrc(t *testing.T) {
+	checkFindings(t, "cross-package-src", `
+cc_library(
+    name = "foo",
+    srcs = [
+        "foo.cc",
+        ":generated.cc",
+        "//the_package:bar.cc",
+    ],
+)
+
+genrule(
+    name = "gen",
+    outs = ["generated.cc"],
+    cmd = "touch $@",
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindings(t, "cross-package-src", `
+cc_library(
+    name = "foo",
+    srcs = [
+        "foo.cc",
+        "//other/package:bar.cc",
+        "//other/package:baz.cc",
+    ],
+)
+
+cc_library(
+    name = "bar",
+    srcs = ["@repo//the_package:bar.cc"],
+)`,
+		[]string{
+			`:5: The source "//other/package:bar.cc" belongs to another package. Consider using a "filegroup" in that package instead.`,
+			`:6: The source "//other/package:baz.cc" belongs to another package. Consider using a "filegroup" in that package instead.`,
+			`:12: The source "@repo//the_package:bar.cc" belongs to another package. Consider using a "filegroup" in that package instead.`,
+		},
+		scopeBuild)
+
+	// Nothing is reported if the package is unknown
+	f, err := build.ParseBuild("BUILD", []byte(`cc_library(
+    name = "foo",
+    srcs = ["//other/package:bar.cc"],
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings := FileWarnings(f, "", []string{"cross-package-src"}, false); len(findings) != 0 {
+		t.Errorf("got %d findings for an unknown package, want 0", len(findings))
+	}
+}
+
 func TestKwargsInBuildFilesWarning(t *testing.T) {
 	checkFindings(t, "build-args-kwargs", `
 cc_library(
@@ -111,3 +173,1691 @@ foo(not bar(-kgs))`,
 		[]string{},
 		scopeBuild)
 }
+
+func TestRedundantDefault(t *testing.T) {
+	checkFindingsAndFix(t, "redundant-default", `
+cc_library(
+    name = "foo",
+    linkstatic = False,
+    alwayslink = False,
+    srcs = ["foo.cc"],
+)`, `
+cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+)`,
+		[]string{
+			`:3: The attribute "linkstatic" of "cc_library" is set to its default value False and can be removed.`,
+			`:4: The attribute "alwayslink" of "cc_library" is set to its default value False and can be removed.`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "redundant-default", `
+cc_library(
+    name = "foo",
+    linkstatic = True,
+)
+
+java_library(
+    name = "bar",
+    neverlink = select({
+        ":cond": True,
+        "//conditions:default": False,
+    }),
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindings(t, "redundant-default", `
+my_macro(
+    name = "foo",
+    linkstatic = False,
+)
+
+cc_binary(
+    name = "bar",
+    linkstatic = False,
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestExportsNonexistentFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exports_files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"exists.txt", "sub/exists.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := build.Parse("the_package/BUILD", []byte(`exports_files([
+    "exists.txt",
+    "missing.txt",
+    "sub/exists.txt",
+    "sub/missing.txt",
+    ":label.txt",
+    "//foo:bar.txt",
+    VARIABLE,
+])
+
+exports_files(FILES)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &WarningContext{Pkg: "the_package", Dir: dir}
+	findings := FileWarningsWithContext(f, ctx, []string{"exports-nonexistent-file"}, false)
+	expected := []string{
+		`The file "missing.txt" exported by "exports_files" doesn't exist in the package.`,
+		`The file "sub/missing.txt" exported by "exports_files" doesn't exist in the package.`,
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("got %d findings, want %d", len(findings), len(expected))
+	}
+	for i, finding := range findings {
+		if finding.Message != expected[i] {
+			t.Errorf("got %q, want %q", finding.Message, expected[i])
+		}
+	}
+
+	// The check is skipped if the directory is unknown
+	if findings := FileWarnings(f, "the_package", []string{"exports-nonexistent-file"}, false); len(findings) != 0 {
+		t.Errorf("got %d findings without a directory, want 0", len(findings))
+	}
+
+	// Only BUILD files are checked
+	f.Type = build.TypeBzl
+	if findings := FileWarningsWithContext(f, ctx, []string{"exports-nonexistent-file"}, false); len(findings) != 0 {
+		t.Errorf("got %d findings for a .bzl file, want 0", len(findings))
+	}
+}
+
+func TestDeprecatedBind(t *testing.T) {
+	checkFindings(t, "deprecated-bind", `
+http_archive(
+    name = "foo",
+    url = "https://example.com/foo.tar.gz",
+)
+
+bind(
+    name = "foo_lib",
+    actual = "@foo//:lib",
+)`,
+		[]string{`:6: The "bind" function is deprecated. Refer to the target directly or create an "alias" rule in a BUILD file and use its label instead.`},
+		scopeWorkspace)
+
+	checkFindings(t, "deprecated-bind", `
+http_archive(
+    name = "foo",
+    url = "https://example.com/foo.tar.gz",
+)`,
+		[]string{},
+		scopeWorkspace)
+}
+
+func TestGlobInSingularAttr(t *testing.T) {
+	checkFindings(t, "glob-singular-attr", `
+java_import(
+    name = "foo",
+    jars = glob(["*.jar"]),
+    srcjar = glob(["*-src.jar"]),
+)
+
+py_binary(
+    name = "bar",
+    srcs = glob(["*.py"]),
+    main = glob(["main*.py"]),
+)`,
+		[]string{
+			`:4: The attribute "srcjar" of "java_import" expects a single file, but "glob" can match any number of files.`,
+			`:10: The attribute "main" of "py_binary" expects a single file, but "glob" can match any number of files.`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "glob-singular-attr", `
+java_import(
+    name = "foo",
+    jars = glob(["*.jar"]),
+    srcjar = "foo-src.jar",
+)
+
+my_macro(
+    name = "bar",
+    main = glob(["main*.py"]),
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestNonASCIILabel(t *testing.T) {
+	checkFindings(t, "non-ascii-label", `
+cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+    deps = [":bar"],
+    copts = ["-DNAME=føø"],
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindings(t, "non-ascii-label", `
+cc_library(
+    name = "foo",
+    deps = [":bar”"],
+)`,
+		[]string{`:3: The value ":bar”" of the attribute "deps" contains the non-ASCII character U+201D. Target names and labels should only contain ASCII characters.`},
+		scopeBuild)
+
+	const zeroWidthSpace = "\u200b"
+	checkFindingsAndFix(t, "non-ascii-label", `
+cc_library(
+    name = "foo`+zeroWidthSpace+`",
+    deps = [
+        ":bar",
+        "//baz`+zeroWidthSpace+`:qux",
+    ],
+)`, `
+cc_library(
+    name = "foo",
+    deps = [
+        ":bar",
+        "//baz:qux",
+    ],
+)`,
+		[]string{
+			`:2: The value "foo\u200b" of the attribute "name" contains the non-ASCII character U+200B. Target names and labels should only contain ASCII characters.`,
+			`:5: The value "//baz\u200b:qux" of the attribute "deps" contains the non-ASCII character U+200B. Target names and labels should only contain ASCII characters.`,
+		},
+		scopeBuild)
+}
+
+func TestPublicVisibilityMix(t *testing.T) {
+	checkFindingsAndFix(t, "public-visibility-mix", `
+cc_library(
+    name = "foo",
+    visibility = [
+        "//foo:__pkg__",
+        "//visibility:public",
+        "//bar:__subpackages__",
+    ],
+)`, `
+cc_library(
+    name = "foo",
+    visibility = ["//visibility:public"],
+)`,
+		[]string{
+			`:4: The visibility entry "//foo:__pkg__" is redundant, "//visibility:public" already makes the target visible to all packages.`,
+			`:6: The visibility entry "//bar:__subpackages__" is redundant, "//visibility:public" already makes the target visible to all packages.`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "public-visibility-mix", `
+cc_library(
+    name = "foo",
+    visibility = ["//visibility:public"],
+)
+
+cc_library(
+    name = "bar",
+    visibility = ["//visibility:private"],
+)
+
+cc_library(
+    name = "baz",
+    visibility = ["//foo:__pkg__", "//bar:__pkg__"],
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestEmptyTestSuite(t *testing.T) {
+	checkFindings(t, "empty-test-suite", `
+test_suite(
+    name = "foo",
+)
+
+test_suite(
+    name = "bar",
+    tests = [],
+)`,
+		[]string{
+			`:1: The test suite "foo" doesn't list any tests, so it includes all tests of the package. List the tests explicitly or select them by "tags".`,
+			`:5: The test suite "bar" doesn't list any tests, so it includes all tests of the package. List the tests explicitly or select them by "tags".`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "empty-test-suite", `
+test_suite(
+    name = "foo",
+    tests = [":foo_test"],
+)
+
+test_suite(
+    name = "bar",
+    tags = ["small"],
+)
+
+test_suite(
+    name = "baz",
+    tests = TESTS,
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestExportsNotInDeps(t *testing.T) {
+	checkFindings(t, "exports-not-in-deps", `
+java_library(
+    name = "foo",
+    exports = [":bar"],
+    deps = [
+        ":bar",
+        ":baz",
+    ],
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindingsAndFix(t, "exports-not-in-deps", `
+java_library(
+    name = "foo",
+    exports = [
+        ":bar",
+        ":baz",
+        ":qux",
+    ],
+    deps = [":bar"],
+)
+
+java_import(
+    name = "imp",
+    jars = ["imp.jar"],
+    exports = [":bar"],
+)`, `
+java_library(
+    name = "foo",
+    exports = [
+        ":bar",
+        ":baz",
+        ":qux",
+    ],
+    deps = [
+        ":bar",
+        ":baz",
+        ":qux",
+    ],
+)
+
+java_import(
+    name = "imp",
+    jars = ["imp.jar"],
+    exports = [":bar"],
+    deps = [":bar"],
+)`,
+		[]string{
+			`:5: The label ":baz" is exported by "foo" but isn't listed in its "deps".`,
+			`:6: The label ":qux" is exported by "foo" but isn't listed in its "deps".`,
+			`:14: The label ":bar" is exported by "imp" but isn't listed in its "deps".`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "exports-not-in-deps", `
+java_library(
+    name = "foo",
+    exports = glob(["*.jar"]),
+    deps = [":bar"],
+)
+
+java_library(
+    name = "bar",
+    exports = [":baz"],
+    deps = glob(["*.jar"]),
+)
+
+cc_library(
+    name = "baz",
+    exports = [":qux"],
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestTupleAttr(t *testing.T) {
+	checkFindingsAndFix(t, "tuple-attr", `
+cc_library(
+    name = "foo",
+    srcs = ("foo.cc",),
+    deps = (
+        ":bar",
+        ":baz",
+    ),
+)`, `
+cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+    deps = [
+        ":bar",
+        ":baz",
+    ],
+)`,
+		[]string{
+			`:3: The attribute "srcs" of "cc_library" should be a list, not a tuple.`,
+			`:4: The attribute "deps" of "cc_library" should be a list, not a tuple.`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "tuple-attr", `
+PAIR = ("a", "b")
+
+cc_library(
+    name = "foo",
+    deps = [":bar"],
+    args = foo(("a", "b")),
+)
+
+my_macro(
+    name = "bar",
+    pair = ("a", "b"),
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestVerboseLabel(t *testing.T) {
+	checkFindingsAndFix(t, "verbose-label", `
+cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+    deps = [
+        "//foo/bar:bar",
+        "//foo/bar:baz",
+        "@repo//baz:baz",
+        ":self",
+        "//:root",
+    ],
+    data = "//data:data",
+)`, `
+cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+    deps = [
+        "//foo/bar",
+        "//foo/bar:baz",
+        "@repo//baz",
+        ":self",
+        "//:root",
+    ],
+    data = "//data",
+)`,
+		[]string{
+			`:5: The label "//foo/bar:bar" can be written in the canonical form "//foo/bar".`,
+			`:7: The label "@repo//baz:baz" can be written in the canonical form "@repo//baz".`,
+			`:11: The label "//data:data" can be written in the canonical form "//data".`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "verbose-label", `
+cc_library(
+    name = "foo:foo",
+    copts = ["//foo:foo"],
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestDeprecatedRepositoryRule(t *testing.T) {
+	checkFindings(t, "deprecated-repository-rule", `
+maven_jar(
+    name = "com_google_guava_guava",
+    artifact = "com.google.guava:guava:28.0-jre",
+)`,
+		[]string{`:1: The repository rule "maven_jar" is deprecated. Use "maven_install" from rules_jvm_external (https://github.com/bazelbuild/rules_jvm_external) instead.`},
+		scopeWorkspace)
+
+	checkFindings(t, "deprecated-repository-rule", `
+load("@rules_jvm_external//:defs.bzl", "maven_install")
+
+maven_install(
+    artifacts = ["com.google.guava:guava:28.0-jre"],
+    repositories = ["https://repo1.maven.org/maven2"],
+)`,
+		[]string{},
+		scopeWorkspace)
+}
+
+func TestRuleInControlFlow(t *testing.T) {
+	checkFindings(t, "rule-in-control-flow", `
+cc_library(
+    name = "foo",
+    srcs = [x for x in glob(["*.cc"]) if x != "bar.cc"],
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindings(t, "rule-in-control-flow", `
+if CONDITION:
+    cc_library(name = "foo")
+
+for name in NAMES:
+    if name != "bar":
+        cc_test(
+            name = name + "_test",
+            srcs = [name + "_test.cc"],
+        )
+    else:
+        print("skipping", name)
+        fail("unexpected name")
+        NAMES.append(name)`,
+		[]string{
+			`:2: The rule "cc_library" is defined inside a loop or a conditional statement. Move the logic to a macro in a .bzl file or declare the rules at the top level.`,
+			`:6: The rule "cc_test" is defined inside a loop or a conditional statement. Move the logic to a macro in a .bzl file or declare the rules at the top level.`,
+		},
+		scopeBuild)
+}
+
+func TestRedundantPackageGroup(t *testing.T) {
+	checkFindingsAndFix(t, "redundant-package-group", `
+package_group(
+    name = "foo",
+    packages = [
+        "//foo/bar/...",
+        "//foo/...",
+        "//foo/baz",
+        "//foo",
+    ],
+)`, `
+package_group(
+    name = "foo",
+    packages = ["//foo/..."],
+)`,
+		[]string{
+			`:4: The package specification "//foo/bar/..." is redundant, it's already covered by "//foo/...".`,
+			`:6: The package specification "//foo/baz" is redundant, it's already covered by "//foo/...".`,
+			`:7: The package specification "//foo" is redundant, it's already covered by "//foo/...".`,
+		},
+		scopeBuild)
+
+	checkFindingsAndFix(t, "redundant-package-group", `
+package_group(
+    name = "foo",
+    packages = ["//...", "//foo", "//foo"],
+)`, `
+package_group(
+    name = "foo",
+    packages = ["//..."],
+)`,
+		[]string{
+			`:3: The package specification "//foo" is redundant, it's already covered by "//...".`,
+			`:3: The package specification "//foo" is redundant, it's already covered by "//...".`,
+		},
+		scopeBuild)
+
+	checkFindingsAndFix(t, "redundant-package-group", `
+package_group(
+    name = "foo",
+    packages = ["//foo", "//bar", "//foo"],
+)`, `
+package_group(
+    name = "foo",
+    packages = ["//foo", "//bar"],
+)`,
+		[]string{
+			`:3: The package specification "//foo" is redundant, it's already covered by "//foo".`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "redundant-package-group", `
+package_group(
+    name = "foo",
+    packages = [
+        "//foo/...",
+        "//foobar/...",
+        "//bar",
+        "-//foo/bar/...",
+        "-//...",
+    ],
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestBinaryNamedTest(t *testing.T) {
+	checkFindings(t, "binary-named-test", `
+cc_binary(name = "foo")
+
+cc_binary(
+    name = "foo_test",
+    srcs = ["foo_test.cc"],
+)
+
+cc_binary(name = "bar_unittest")
+
+cc_test(name = "baz_test")`,
+		[]string{
+			`:3: The binary "foo_test" is named like a test, consider using "cc_test" instead of "cc_binary".`,
+			`:8: The binary "bar_unittest" is named like a test, consider using "cc_test" instead of "cc_binary".`,
+		},
+		scopeBuild)
+
+	defer func(suffixes []string) { tables.TestNameSuffixes = suffixes }(tables.TestNameSuffixes)
+	tables.TestNameSuffixes = []string{"_check"}
+	checkFindings(t, "binary-named-test", `
+cc_binary(name = "foo_test")
+
+cc_binary(name = "foo_check")`,
+		[]string{
+			`:3: The binary "foo_check" is named like a test, consider using "cc_test" instead of "cc_binary".`,
+		},
+		scopeBuild)
+}
+
+func TestRepoBuildFilePath(t *testing.T) {
+	checkFindings(t, "repo-build-file-path", `
+new_local_repository(
+    name = "foo",
+    path = "/usr/local/foo",
+    build_file = "third_party/foo.BUILD",
+)
+
+new_local_repository(
+    name = "bar",
+    path = "/usr/local/bar",
+    build_file = "//third_party:bar.BUILD",
+)
+
+new_git_repository(
+    name = "baz",
+    build_file = "@other//:baz.BUILD",
+)`,
+		[]string{},
+		scopeWorkspace)
+
+	checkFindings(t, "repo-build-file-path", `
+new_local_repository(
+    name = "foo",
+    path = "/usr/local/foo",
+    build_file = "/usr/local/foo/BUILD",
+)
+
+new_git_repository(
+    name = "bar",
+    build_file = "third_party/../../bar.BUILD",
+)
+
+local_repository(
+    name = "baz",
+    build_file = "../baz.BUILD",
+)`,
+		[]string{
+			`:4: The "build_file" of the repository "foo" is an absolute path. Put the BUILD file into the workspace and refer to it with a label instead.`,
+			`:9: The "build_file" of the repository "bar" points outside of the workspace. Put the BUILD file into the workspace and refer to it with a label instead.`,
+		},
+		scopeWorkspace)
+}
+
+func TestBackslashLabel(t *testing.T) {
+	checkFindingsAndFix(t, "backslash-label", `
+cc_library(
+    name = "foo",
+    srcs = ["foo\\bar.cc"],
+    deps = [
+        "//foo\\bar:baz",
+        ":qux",
+    ],
+    copts = ["-Ifoo\\bar"],
+    defines = ["PATH=\"C:\\foo\""],
+)`, `
+cc_library(
+    name = "foo",
+    srcs = ["foo/bar.cc"],
+    deps = [
+        "//foo/bar:baz",
+        ":qux",
+    ],
+    copts = ["-Ifoo\\bar"],
+    defines = ["PATH=\"C:\\foo\""],
+)`,
+		[]string{
+			`:3: The label "foo\\bar.cc" of the attribute "srcs" contains backslashes. Labels should use forward slashes.`,
+			`:5: The label "//foo\\bar:baz" of the attribute "deps" contains backslashes. Labels should use forward slashes.`,
+		},
+		scopeBuild)
+}
+
+func TestGenruleCmdOutputRef(t *testing.T) {
+	checkFindings(t, "genrule-cmd-output-ref", `
+genrule(
+    name = "foo",
+    outs = ["foo.txt"],
+    cmd = "echo foo > $@",
+)
+
+genrule(
+    name = "bar",
+    outs = ["bar.txt", "baz.txt"],
+    cmd = "touch $(OUTS) && echo $$@ && ls $(@D)",
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindings(t, "genrule-cmd-output-ref", `
+genrule(
+    name = "foo",
+    outs = [
+        "foo.txt",
+        "bar.txt",
+    ],
+    cmd = "echo foo > $@",
+    cmd_bash = "echo " + "foo > $@",
+)`,
+		[]string{
+			`:7: The "$@" variable in the "cmd" attribute can only be used if the genrule "foo" has a single output. Use "$(OUTS)" or refer to the outputs with "$(location <output>)" instead.`,
+			`:8: The "$@" variable in the "cmd_bash" attribute can only be used if the genrule "foo" has a single output. Use "$(OUTS)" or refer to the outputs with "$(location <output>)" instead.`,
+		},
+		scopeBuild)
+}
+
+func TestVisibilityPackageGroup(t *testing.T) {
+	checkFindings(t, "visibility-package-group", `
+package(default_visibility = [":friends"])
+
+package_group(
+    name = "friends",
+    packages = ["//foo/..."],
+)
+
+cc_library(
+    name = "foo",
+    visibility = [
+        ":friends",
+        "//the_package:friends",
+        "//other/package:group",
+        "@repo//the_package:group",
+        "//bar:__pkg__",
+        ":__subpackages__",
+        "//visibility:private",
+    ],
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindings(t, "visibility-package-group", `
+package(default_visibility = ["//the_package:enemies"])
+
+package_group(
+    name = "friends",
+    packages = ["//foo/..."],
+)
+
+cc_library(
+    name = "foo",
+    visibility = [
+        ":friends",
+        ":fiends",
+        ":enemies",
+    ],
+)`,
+		[]string{
+			`:1: The visibility entry "//the_package:enemies" refers to a package group "enemies" which isn't defined in this package.`,
+			`:12: The visibility entry ":fiends" refers to a package group "fiends" which isn't defined in this package.`,
+			`:13: The visibility entry ":enemies" refers to a package group "enemies" which isn't defined in this package.`,
+		},
+		scopeBuild)
+
+	// The package is passed by the caller, the path of the file may be absolute.
+	f, err := build.ParseBuild("/home/user/workspace/foo/BUILD", []byte(`package(default_visibility = ["//foo:friends"])
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings := FileWarnings(f, "foo", []string{"visibility-package-group"}, false); len(findings) != 1 {
+		t.Errorf("got %d findings, want 1", len(findings))
+	}
+}
+
+func TestSingularAttrTypo(t *testing.T) {
+	checkFindingsAndFix(t, "singular-attr-typo", `
+cc_library(
+    name = "foo",
+    src = ["foo.cc"],
+    dep = [":bar"],
+)
+
+genrule(
+    name = "gen",
+    srcs = ["a.txt"],
+    src = ["b.txt"],
+    out = ["c.txt"],
+)`, `
+cc_library(
+    name = "foo",
+    srcs = ["foo.cc"],
+    deps = [":bar"],
+)
+
+genrule(
+    name = "gen",
+    srcs = ["a.txt"],
+    src = ["b.txt"],
+    outs = ["c.txt"],
+)`,
+		[]string{
+			`:3: The attribute "src" of the rule "foo" is probably a typo, use "srcs" instead.`,
+			`:4: The attribute "dep" of the rule "foo" is probably a typo, use "deps" instead.`,
+			`:10: The attribute "src" of the rule "gen" is probably a typo, it's ignored in favor of "srcs".`,
+			`:11: The attribute "out" of the rule "gen" is probably a typo, use "outs" instead.`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "singular-attr-typo", `
+copy_file(
+    name = "copy",
+    src = "a.txt",
+    out = "b.txt",
+)
+
+cc_library(
+    name = "foo",
+    out = "foo.o",
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestNestedSelect(t *testing.T) {
+	checkFindings(t, "nested-select", `
+cc_library(
+    name = "foo",
+    srcs = select({
+        ":linux": ["linux.cc"],
+        "//conditions:default": ["default.cc"],
+    }) + select({
+        ":opt": ["opt.cc"],
+        "//conditions:default": [],
+    }),
+)`,
+		[]string{},
+		scopeEverywhere)
+
+	checkFindings(t, "nested-select", `
+cc_library(
+    name = "foo",
+    srcs = select({
+        ":linux": select({
+            ":opt": ["linux_opt.cc"],
+            "//conditions:default": ["linux.cc"],
+        }),
+        "//conditions:default": ["default.cc"],
+    }),
+)`,
+		[]string{
+			`:4: A select() is nested inside another select(). Consider flattening the conditions, e.g. by using config_setting_group or selects.with_or.`,
+		},
+		scopeEverywhere)
+}
+
+func TestMixedLabelList(t *testing.T) {
+	checkFindings(t, "mixed-label-list", `
+cc_library(
+    name = "foo",
+    srcs = [
+        "foo.cc",
+        "subdir/bar.cc",
+    ],
+    deps = [
+        ":bar",
+        "//pkg:baz",
+        "@repo//:qux",
+    ],
+)`,
+		[]string{},
+		scopeBuild)
+
+	checkFindings(t, "mixed-label-list", `
+cc_library(
+    name = "foo",
+    deps = [
+        ":bar",
+        "baz",
+        "//pkg:qux",
+    ],
+)`,
+		[]string{
+			`:5: The value "baz" of the attribute "deps" doesn't look like a label. Labels should start with "//", "@" or ":".`,
+		},
+		scopeBuild)
+}
+
+func TestPyMainInSrcs(t *testing.T) {
+	checkFindingsAndFix(t, "py-main-in-srcs", `
+py_binary(
+    name = "ok",
+    srcs = [
+        "lib.py",
+        "ok_main.py",
+    ],
+    main = "ok_main.py",
+)
+
+py_test(
+    name = "missing",
+    srcs = ["lib.py"],
+    main = "missing_main.py",
+)
+
+py_binary(
+    name = "globbed",
+    srcs = glob(["*.py"]),
+    main = "globbed_main.py",
+)
+
+py_binary(
+    name = "labels",
+    srcs = [
+        ":lib.py",
+        "//the_package:labels_main.py",
+    ],
+    main = ":labels_main.py",
+)
+
+py_binary(
+    name = "subdir",
+    srcs = [":tools/subdir_main.py"],
+    main = "//the_package:tools/subdir_main.py",
+)
+
+py_binary(
+    name = "other_package",
+    srcs = ["//other:other_main.py"],
+    main = "other_main.py",
+)`, `
+py_binary(
+    name = "ok",
+    srcs = [
+        "lib.py",
+        "ok_main.py",
+    ],
+    main = "ok_main.py",
+)
+
+py_test(
+    name = "missing",
+    srcs = [
+        "lib.py",
+        "missing_main.py",
+    ],
+    main = "missing_main.py",
+)
+
+py_binary(
+    name = "globbed",
+    srcs = glob(["*.py"]),
+    main = "globbed_main.py",
+)
+
+py_binary(
+    name = "labels",
+    srcs = [
+        ":lib.py",
+        "//the_package:labels_main.py",
+    ],
+    main = ":labels_main.py",
+)
+
+py_binary(
+    name = "subdir",
+    srcs = [":tools/subdir_main.py"],
+    main = "//the_package:tools/subdir_main.py",
+)
+
+py_binary(
+    name = "other_package",
+    srcs = [
+        "//other:other_main.py",
+        "other_main.py",
+    ],
+    main = "other_main.py",
+)`,
+		[]string{
+			`:13: The main file "missing_main.py" of the rule "missing" is not listed in its "srcs".`,
+			`:40: The main file "other_main.py" of the rule "other_package" is not listed in its "srcs".`,
+		},
+		scopeBuild)
+}
+
+func TestPyMainInSrcsPackage(t *testing.T) {
+	// The package is passed by the caller, the path of the file may be absolute.
+	f, err := build.ParseBuild("/home/user/workspace/foo/BUILD", []byte(`py_binary(
+    name = "x",
+    srcs = ["//foo:main.py"],
+    main = "main.py",
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings := FileWarnings(f, "foo", []string{"py-main-in-srcs"}, false); len(findings) != 0 {
+		t.Errorf("got %d findings, want 0: %s", len(findings), findings[0].Message)
+	}
+	if findings := FileWarnings(f, "bar", []string{"py-main-in-srcs"}, false); len(findings) != 1 {
+		t.Errorf("got %d findings in another package, want 1", len(findings))
+	}
+}
+
+func TestRequiredAttr(t *testing.T) {
+	checkFindings(t, "required-attr", `
+genrule(
+    name = "complete",
+    outs = ["out.txt"],
+    cmd = "touch $@",
+)
+
+genquery(
+    name = "query",
+)
+
+my_macro(
+    name = "unknown",
+)`,
+		[]string{
+			`:7: The rule "query" of kind "genquery" is missing the required attribute "expression".`,
+			`:7: The rule "query" of kind "genquery" is missing the required attribute "scope".`,
+		},
+		scopeBuild)
+}
+
+func TestExportsFilesNoVisibility(t *testing.T) {
+	checkFindings(t, "exports-files-visibility", `
+exports_files(
+    ["a.txt"],
+    visibility = ["//visibility:public"],
+)
+
+exports_files(["b.txt"], ["//foo:__pkg__"])
+
+exports_files(["c.txt"])`,
+		[]string{
+			`:8: The visibility of the exported files is not set. The default depends on the Bazel version and flags, consider setting it explicitly.`,
+		},
+		scopeBuild)
+}
+
+func TestReservedConfigSetting(t *testing.T) {
+	defer func(names map[string]bool) { tables.ReservedConfigSettingNames = names }(tables.ReservedConfigSettingNames)
+	tables.ReservedConfigSettingNames = map[string]bool{"linux": true}
+
+	checkFindings(t, "reserved-config-setting", `
+config_setting(
+    name = "linux",
+    constraint_values = ["@platforms//os:linux"],
+)
+
+config_setting(
+    name = "is_linux",
+    constraint_values = ["@platforms//os:linux"],
+)
+
+cc_library(
+    name = "linux",
+)`,
+		[]string{
+			`:2: The name "linux" of the config_setting is reserved, e.g. it's also used for a platform constraint. Consider renaming it.`,
+		},
+		scopeBuild)
+}
+
+func TestRequiredTestTag(t *testing.T) {
+	defer func(tags []string) { tables.RequiredTestTags = tags }(tables.RequiredTestTags)
+	tables.RequiredTestTags = []string{"unit", "integration", "e2e"}
+
+	checkFindings(t, "required-test-tag", `
+java_test(
+    name = "tagged",
+    tags = [
+        "integration",
+        "manual",
+    ],
+)
+
+cc_test(
+    name = "untagged",
+    tags = ["manual"],
+)
+
+cc_library(
+    name = "lib",
+)`,
+		[]string{
+			`:9: The test "untagged" should have one of the following tags: unit, integration, e2e.`,
+		},
+		scopeBuild)
+}
+
+func TestMainRepoLabel(t *testing.T) {
+	checkFindingsAndFix(t, "main-repo-label", `
+cc_library(
+    name = "foo",
+    deps = [
+        "@//foo:bar",
+        "@other//foo:bar",
+    ],
+)`, `
+cc_library(
+    name = "foo",
+    deps = [
+        "//foo:bar",
+        "@other//foo:bar",
+    ],
+)`,
+		[]string{
+			`:4: The label "@//foo:bar" of the attribute "deps" refers to the main repository explicitly, use "//foo:bar" instead.`,
+		},
+		scopeBuild)
+}
+
+func TestGlobOverlap(t *testing.T) {
+	checkFindings(t, "glob-overlap", `
+cc_library(
+    name = "overlap",
+    srcs = glob(["*.cc"]) + [
+        "main.cc",
+        "gen/generated.cc",
+    ],
+)
+
+cc_library(
+    name = "no_overlap",
+    srcs = glob(
+        ["**/*.cc"],
+        exclude = ["main.cc"],
+    ) + [
+        "main.cc",
+        "header.h",
+    ],
+)`,
+		[]string{
+			`:4: The source "main.cc" of the rule "overlap" is also matched by a glob() in the same attribute.`,
+		},
+		scopeBuild)
+}
+
+func TestGlobPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.cc", "foo.cc", true},
+		{"*.cc", "dir/foo.cc", false},
+		{"**/*.cc", "foo.cc", true},
+		{"**/*.cc", "a/b/foo.cc", true},
+		{"dir/**", "dir/a/b.txt", true},
+		{"dir/*.h", "other/a.h", false},
+		{"foo.[ch]", "foo.c", false},
+	}
+	for _, tt := range tests {
+		if got := globPatternMatches(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("globPatternMatches(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTestonlyDep(t *testing.T) {
+	checkFindings(t, "testonly-dep", `
+java_library(
+    name = "testutil",
+    testonly = True,
+)
+
+java_library(
+    name = "lib",
+    deps = [
+        ":testutil",
+        "//the_package:testutil",
+        "//other/pkg:testutil",
+        "@repo//:testutil",
+    ],
+)
+
+java_library(
+    name = "lib2",
+    deps = select({
+        ":cond": ["testutil"],
+        "//conditions:default": [],
+    }),
+)
+
+java_test(
+    name = "test",
+    deps = [":testutil"],
+)
+
+java_library(
+    name = "other_testutil",
+    testonly = 1,
+    deps = [":testutil"],
+)`,
+		[]string{
+			`:9: The rule "lib" depends on the testonly target ":testutil" but isn't testonly itself.`,
+			`:10: The rule "lib" depends on the testonly target "//the_package:testutil" but isn't testonly itself.`,
+			`:19: The rule "lib2" depends on the testonly target "testutil" but isn't testonly itself.`,
+		},
+		scopeBuild)
+
+	// The package is passed by the caller, the path of the file may be absolute.
+	f, err := build.ParseBuild("/home/user/workspace/foo/BUILD", []byte(`java_library(
+    name = "testutil",
+    testonly = True,
+)
+
+java_library(
+    name = "lib",
+    deps = ["//foo:testutil"],
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings := FileWarnings(f, "foo", []string{"testonly-dep"}, false); len(findings) != 1 {
+		t.Errorf("got %d findings, want 1", len(findings))
+	}
+}
+
+func TestMissingStripPrefix(t *testing.T) {
+	checkFindings(t, "missing-strip-prefix", `
+http_archive(
+    name = "foo",
+    urls = ["https://github.com/org/foo/archive/v1.2.3.tar.gz"],
+)
+
+http_archive(
+    name = "bar",
+    url = "https://github.com/org/bar/archive/refs/tags/v1.0.zip",
+)
+
+http_archive(
+    name = "baz",
+    strip_prefix = "baz-1.2.3",
+    urls = ["https://github.com/org/baz/archive/v1.2.3.tar.gz"],
+)
+
+http_archive(
+    name = "release",
+    urls = ["https://github.com/org/release/releases/download/v1.0/release.tar.gz"],
+)`,
+		[]string{
+			`:1: The http_archive "foo" downloads a GitHub source archive but has no "strip_prefix". The contents of such archives are located in a top-level directory.`,
+			`:6: The http_archive "bar" downloads a GitHub source archive but has no "strip_prefix". The contents of such archives are located in a top-level directory.`,
+		},
+		scopeWorkspace)
+}
+
+func TestDefaultTestonly(t *testing.T) {
+	checkFindings(t, "default-testonly", `
+package(
+    default_testonly = True,
+    default_visibility = ["//visibility:public"],
+)`,
+		[]string{
+			`:2: "default_testonly = True" makes all targets of the package testonly, they can't be used by non-test targets from other packages. Consider setting "testonly = True" on the test-only targets individually or moving them to a separate package.`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "default-testonly", `
+package(default_visibility = ["//visibility:public"])
+
+package(default_testonly = False)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestShSingleScript(t *testing.T) {
+	checkFindings(t, "sh-single-script", `
+sh_binary(
+    name = "single",
+    srcs = ["single.sh"],
+)
+
+sh_test(
+    name = "two",
+    srcs = [
+        "two.sh",
+        "helper.sh",
+    ],
+)
+
+sh_binary(
+    name = "globbed",
+    srcs = glob(["*.sh"]),
+)
+
+genrule(
+    name = "gen",
+    srcs = ["a", "b"],
+)`,
+		[]string{
+			`:8: The rule "two" of kind "sh_test" should have a single script in "srcs", found 2. Move the other files to "data" or to a "sh_library" in "deps".`,
+		},
+		scopeBuild)
+}
+
+func TestNonLiteralName(t *testing.T) {
+	checkFindings(t, "non-literal-name", `
+cc_library(
+    name = "lib",
+)
+
+cc_library(
+    name = PREFIX + "lib",
+)
+
+cc_library(
+    name = make_name("lib"),
+)`,
+		[]string{
+			`:6: The name of the "cc_library" rule is not a string literal, tools that index targets by their names can't find it.`,
+			`:10: The name of the "cc_library" rule is not a string literal, tools that index targets by their names can't find it.`,
+		},
+		scopeBuild)
+}
+
+func TestIncludesEscape(t *testing.T) {
+	checkFindings(t, "includes-escape", `
+cc_library(
+    name = "lib",
+    includes = [
+        "include",
+        "foo/../include",
+        "../other/include",
+        "/usr/include",
+    ],
+)
+
+java_library(
+    name = "java",
+    includes = ["../foo"],
+)`,
+		[]string{
+			`:6: The include directory "../other/include" of the rule "lib" points outside of the package, include paths should be relative to the package.`,
+			`:7: The include directory "/usr/include" of the rule "lib" is an absolute path, include paths should be relative to the package.`,
+		},
+		scopeBuild)
+}
+
+func TestEmptyStringInList(t *testing.T) {
+	checkFindingsAndFix(t, "empty-string-in-list", `
+cc_library(
+    name = "lib",
+    srcs = ["lib.cc", ""],
+    deps = [
+        ":dep",
+        "",
+        "",
+    ] + select({
+        ":opt": [""],
+        "//conditions:default": [],
+    }),
+    copts = [""],
+)
+
+cc_library(
+    name = "other",
+    srcs = ["other.cc"],
+    deps = [":lib"],
+)`, `
+cc_library(
+    name = "lib",
+    srcs = ["lib.cc"],
+    deps = [":dep"] + select({
+        ":opt": [],
+        "//conditions:default": [],
+    }),
+    copts = [""],
+)
+
+cc_library(
+    name = "other",
+    srcs = ["other.cc"],
+    deps = [":lib"],
+)`,
+		[]string{
+			`:3: The attribute "srcs" of the rule "lib" contains an empty string.`,
+			`:6: The attribute "deps" of the rule "lib" contains an empty string.`,
+			`:7: The attribute "deps" of the rule "lib" contains an empty string.`,
+			`:9: The attribute "deps" of the rule "lib" contains an empty string.`,
+		},
+		scopeBuild)
+}
+
+func TestJavaBinaryMainClass(t *testing.T) {
+	checkFindings(t, "java-binary-main-class", `
+java_binary(
+    name = "single",
+    srcs = ["Single.java"],
+)
+
+java_binary(
+    name = "ambiguous",
+    srcs = [
+        "Foo.java",
+        "Bar.java",
+    ],
+)
+
+java_binary(
+    name = "Tool",
+    srcs = [
+        "Tool.java",
+        "Helper.java",
+    ],
+)
+
+java_binary(
+    name = "explicit",
+    srcs = [
+        "Foo.java",
+        "Bar.java",
+    ],
+    main_class = "com.example.Foo",
+)
+
+java_binary(
+    name = "from_deps",
+    srcs = [
+        "Foo.java",
+        "Bar.java",
+    ],
+    runtime_deps = [":main"],
+)
+
+java_binary(
+    name = "globbed",
+    srcs = glob(["*.java"]),
+)`,
+		[]string{
+			`:6: The java_binary "ambiguous" has 2 sources and no "main_class", its entry point can't be determined. Set "main_class" explicitly.`,
+		},
+		scopeBuild)
+}
+
+func TestVisibilityNotList(t *testing.T) {
+	checkFindingsAndFix(t, "visibility-not-list", `
+package(default_visibility = "//visibility:public")
+
+cc_library(
+    name = "foo",
+    visibility = "//foo:__pkg__",
+)
+
+cc_library(
+    name = "bar",
+    visibility = ["//bar:__pkg__"],
+)`, `
+package(default_visibility = ["//visibility:public"])
+
+cc_library(
+    name = "foo",
+    visibility = ["//foo:__pkg__"],
+)
+
+cc_library(
+    name = "bar",
+    visibility = ["//bar:__pkg__"],
+)`,
+		[]string{
+			`:1: The attribute "default_visibility" of "package" should be a list, not a string.`,
+			`:5: The attribute "visibility" of "cc_library" should be a list, not a string.`,
+		},
+		scopeBuild)
+}
+
+func TestSelectKey(t *testing.T) {
+	checkFindings(t, "select-key", `
+cc_library(
+    name = "lib",
+    copts = select({
+        "//config:opt": ["-O2"],
+        ":dbg": ["-g"],
+        "@platforms//os:linux": [],
+        "opt": ["-O3"],
+        CONDITION: [],
+        "//conditions:default": [],
+    }),
+)`,
+		[]string{
+			`:7: The select() key "opt" doesn't look like a label. Keys should be labels of config_setting targets starting with "//", "@" or ":".`,
+		},
+		scopeEverywhere)
+}
+
+func TestExecTools(t *testing.T) {
+	checkFindingsAndFix(t, "exec-tools", `
+genrule(
+    name = "gen",
+    outs = ["out.txt"],
+    cmd = "$(location :tool) > $@",
+    tools = [":tool"],
+)
+
+genrule(
+    name = "both",
+    outs = ["both.txt"],
+    cmd = "$(location :tool) > $@",
+    tools = [":other"],
+    exec_tools = [":tool"],
+)
+
+cc_library(
+    name = "lib",
+    tools = [":tool"],
+)`, `
+genrule(
+    name = "gen",
+    outs = ["out.txt"],
+    cmd = "$(location :tool) > $@",
+    exec_tools = [":tool"],
+)
+
+genrule(
+    name = "both",
+    outs = ["both.txt"],
+    cmd = "$(location :tool) > $@",
+    tools = [":other"],
+    exec_tools = [":tool"],
+)
+
+cc_library(
+    name = "lib",
+    tools = [":tool"],
+)`,
+		[]string{
+			`:5: The attribute "tools" of the rule "gen" builds its dependencies for the host, use "exec_tools" instead to build them for the execution platform.`,
+			`:12: The attribute "tools" of the rule "both" builds its dependencies for the host, move them to "exec_tools".`,
+		},
+		scopeBuild)
+
+	checkFindings(t, "exec-tools", `
+genrule(
+    name = "gen",
+    outs = ["out.txt"],
+    cmd = "$(location :tool) > $@",
+    exec_tools = [":tool"],
+)`,
+		[]string{},
+		scopeBuild)
+}
+
+func TestAlwayslinkNoSrcs(t *testing.T) {
+	checkFindings(t, "alwayslink-no-srcs", `
+cc_library(
+    name = "headers",
+    hdrs = ["lib.h"],
+    alwayslink = True,
+)
+
+cc_library(
+    name = "empty",
+    srcs = [],
+    alwayslink = 1,
+)
+
+cc_library(
+    name = "lib",
+    srcs = ["lib.cc"],
+    alwayslink = True,
+)
+
+cc_library(
+    name = "globbed",
+    srcs = glob(["*.cc"]),
+    alwayslink = True,
+)
+
+cc_library(
+    name = "not_alwayslink",
+    hdrs = ["lib.h"],
+    alwayslink = False,
+)`,
+		[]string{
+			`:4: The rule "headers" has no "srcs", "alwayslink" has no effect on a library without object files.`,
+			`:10: The rule "empty" has no "srcs", "alwayslink" has no effect on a library without object files.`,
+		},
+		scopeBuild)
+}
+
+func TestNamingConvention(t *testing.T) {
+	defer func(conventions map[string]string) { tables.NamingConventions = conventions }(tables.NamingConventions)
+	tables.NamingConventions = map[string]string{
+		"*_test":       ".*_test",
+		"java_library": "[a-z_]+",
+	}
+
+	checkFindings(t, "naming-convention", `
+cc_test(
+    name = "lib_test",
+)
+
+java_test(
+    name = "LibTest",
+)
+
+java_library(
+    name = "MyLib",
+)
+
+cc_library(
+    name = "AnyName",
+)`,
+		[]string{
+			`:6: The name "LibTest" of the "java_test" rule doesn't match the naming convention ".*_test".`,
+			`:10: The name "MyLib" of the "java_library" rule doesn't match the naming convention "[a-z_]+".`,
+		},
+		scopeBuild)
+}
+
+func TestDuplicatedAttr(t *testing.T) {
+	checkFindingsAndFix(t, "duplicated-attr", `
+cc_library(
+    name = "lib",
+    srcs = ["a.cc"],
+    deps = [":a"],
+    srcs = ["b.cc"],
+    deps = [":b"],
+    deps = [":c"],
+)
+
+cc_library(
+    name = "clean",
+    srcs = ["clean.cc"],
+)`, `
+cc_library(
+    name = "lib",
+    srcs = ["b.cc"],
+    deps = [":c"],
+)
+
+cc_library(
+    name = "clean",
+    srcs = ["clean.cc"],
+)`,
+		[]string{
+			`:5: The attribute "srcs" of "cc_library" is set more than once, which is an error. Keep a single value.`,
+			`:6: The attribute "deps" of "cc_library" is set more than once, which is an error. Keep a single value.`,
+			`:7: The attribute "deps" of "cc_library" is set more than once, which is an error. Keep a single value.`,
+		},
+		scopeBuild)
+}
+
+func TestProtoLibrarySrcs(t *testing.T) {
+	checkFindings(t, "proto-library-srcs", `
+proto_library(
+    name = "proto",
+    srcs = [
+        "foo.proto",
+        "foo.cc",
+        ":generated_protos",
+        "//pkg:bar.proto",
+    ],
+)
+
+proto_library(
+    name = "globbed",
+    srcs = glob(["*"]),
+)
+
+cc_library(
+    name = "lib",
+    srcs = ["lib.cc"],
+)`,
+		[]string{
+			`:5: The source "foo.cc" of the proto_library "proto" is not a .proto file.`,
+		},
+		scopeBuild)
+}
+
+func TestRunfilesGlob(t *testing.T) {
+	checkFindings(t, "runfiles-glob", `
+sh_test(
+    name = "recursive",
+    srcs = ["test.sh"],
+    data = glob(["testdata/**"]),
+)
+
+sh_test(
+    name = "concatenated",
+    srcs = ["test.sh"],
+    data = [":tool"] + glob(include = ["res/**/*.txt"]),
+)
+
+sh_test(
+    name = "allow_empty",
+    srcs = ["test.sh"],
+    data = glob(["testdata/**"], allow_empty = False),
+)
+
+sh_test(
+    name = "flat",
+    srcs = ["test.sh"],
+    data = glob(["testdata/*"]),
+)
+
+cc_library(
+    name = "lib",
+    srcs = glob(["**/*.cc"]),
+)`,
+		[]string{
+			`:4: The recursive glob in the "data" attribute of the rule "recursive" doesn't set "allow_empty", set it explicitly to state whether an empty set of runfiles is expected.`,
+			`:10: The recursive glob in the "data" attribute of the rule "concatenated" doesn't set "allow_empty", set it explicitly to state whether an empty set of runfiles is expected.`,
+		},
+		scopeBuild)
+}
@@ -4,6 +4,8 @@ package warn
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/bazelbuild/buildtools/build"
 	"github.com/bazelbuild/buildtools/bzlenv"
 	"github.com/bazelbuild/buildtools/edit"
@@ -158,7 +160,7 @@ func noEffectStatementsCheck(f *build.File, body []build.Expr, isTopLevel, isFun
 		}
 		switch s := (stmt).(type) {
 		case *build.DefStmt, *build.ForStmt, *build.IfStmt, *build.LoadStmt, *build.ReturnStmt,
-			*build.CallExpr, *build.CommentBlock, *build.BranchStmt, *build.AssignExpr:
+			*build.AssertStmt, *build.CallExpr, *build.CommentBlock, *build.BranchStmt, *build.AssignExpr:
 			continue
 		case *build.Comprehension:
 			if !isTopLevel || s.Curly {
@@ -345,6 +347,57 @@ If you want to re-export a symbol, use the following pattern:
 	return findings
 }
 
+func conflictingLoadSymbolWarning(f *build.File) []*LinterFinding {
+	type origin struct {
+		module string
+		line   int
+	}
+	loaded := make(map[string]origin)
+
+	findings := []*LinterFinding{}
+	for _, stmt := range f.Stmt {
+		load, ok := stmt.(*build.LoadStmt)
+		if !ok {
+			continue
+		}
+		for _, to := range load.To {
+			previous, alreadyLoaded := loaded[to.Name]
+			if !alreadyLoaded {
+				loaded[to.Name] = origin{load.Module.Value, to.NamePos.Line}
+				continue
+			}
+			if previous.module == load.Module.Value {
+				// Loading the same symbol twice from the same module is reported by the "load" warning.
+				continue
+			}
+			findings = append(findings, makeLinterFinding(to,
+				fmt.Sprintf(`The symbol %q loaded from %q overrides the one loaded from %q on line %d.`,
+					to.Name, load.Module.Value, previous.module, previous.line)))
+			loaded[to.Name] = origin{load.Module.Value, to.NamePos.Line}
+		}
+	}
+	return findings
+}
+
+func privateSymbolExportWarning(f *build.File) []*LinterFinding {
+	findings := []*LinterFinding{}
+	for _, stmt := range f.Stmt {
+		load, ok := stmt.(*build.LoadStmt)
+		if !ok {
+			continue
+		}
+		for _, from := range load.From {
+			if !strings.HasPrefix(from.Name, "_") {
+				continue
+			}
+			findings = append(findings, makeLinterFinding(from,
+				fmt.Sprintf(`The symbol %q loaded from %q is private to that file, symbols starting with "_" can't be loaded.`,
+					from.Name, load.Module.Value)))
+		}
+	}
+	return findings
+}
+
 // collectLocalVariables traverses statements (e.g. of a function definition) and returns a list
 // of idents for variables defined anywhere inside the function.
 func collectLocalVariables(stmts []build.Expr) []*build.Ident {
@@ -457,6 +457,36 @@ x = "unused"`,
 		scopeEverywhere)
 }
 
+func TestConflictingLoadSymbol(t *testing.T) {
+	checkFindings(t, "conflicting-load-symbol", `
+load(":a.bzl", "foo", "bar")
+load(":b.bzl", "foo")
+load(":a.bzl", "bar")
+load(":c.bzl", bar = "baz")`,
+		[]string{
+			`:2: The symbol "foo" loaded from ":b.bzl" overrides the one loaded from ":a.bzl" on line 1.`,
+			`:4: The symbol "bar" loaded from ":c.bzl" overrides the one loaded from ":a.bzl" on line 1.`,
+		},
+		scopeEverywhere)
+
+	checkFindings(t, "conflicting-load-symbol", `
+load(":a.bzl", "foo")
+load(":a.bzl", "foo", "bar")`,
+		[]string{},
+		scopeEverywhere)
+}
+
+func TestPrivateSymbolLoad(t *testing.T) {
+	checkFindings(t, "private-symbol-load", `
+load(":a.bzl", "public", "_private")
+load(":b.bzl", alias = "_impl", _local = "other")`,
+		[]string{
+			`:1: The symbol "_private" loaded from ":a.bzl" is private to that file, symbols starting with "_" can't be loaded.`,
+			`:2: The symbol "_impl" loaded from ":b.bzl" is private to that file, symbols starting with "_" can't be loaded.`,
+		},
+		scopeEverywhere)
+}
+
 func TestUninitializedVariable(t *testing.T) {
 	checkFindings(t, "uninitialized", `
 def foo(x):
@@ -319,3 +319,40 @@ func unsortedDictItemsWarning(f *build.File, fix bool) []*Finding {
 	})
 	return findings
 }
+
+func trailingCommaWarning(f *build.File) []*LinterFinding {
+	findings := []*LinterFinding{}
+	build.WalkPointers(f, func(expr *build.Expr, stack []build.Expr) {
+		var list []build.Expr
+		var end build.Position
+		switch x := (*expr).(type) {
+		case *build.ListExpr:
+			list, end = x.List, x.End.Pos
+		case *build.DictExpr:
+			list, end = x.List, x.End.Pos
+		case *build.CallExpr:
+			list, end = x.List, x.End.Pos
+			// The printer never adds a comma after *args or **kwargs.
+			if len(x.List) > 0 {
+				if unary, ok := x.List[len(x.List)-1].(*build.UnaryExpr); ok && (unary.Op == "*" || unary.Op == "**") {
+					return
+				}
+			}
+		default:
+			return
+		}
+		if len(list) == 0 || f.HasTrailingComma(*expr) {
+			return
+		}
+		// Only flag the nodes whose closing bracket is on a separate line, these are printed
+		// with a trailing comma, unlike e.g. `foo([...])`.
+		if _, lastEnd := list[len(list)-1].Span(); lastEnd.Line == end.Line {
+			return
+		}
+		// The printer adds the missing comma, replacing the node with itself is enough.
+		findings = append(findings, makeLinterFinding(*expr,
+			"The last element of a multi-line list, dict or call should be followed by a trailing comma.",
+			LinterReplacement{Old: expr, New: *expr}))
+	})
+	return findings
+}
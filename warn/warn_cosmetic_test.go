@@ -110,6 +110,18 @@ package()`,
 }
 
 func TestLoadOnTop(t *testing.T) {
+	checkFindings(t, "load-on-top", `
+"""Docstring"""
+
+# Comment block
+
+load(":f.bzl", "x")
+load(":g.bzl", "y")
+
+x()
+y()`,
+		[]string{}, scopeDefault|scopeBzl)
+
 	checkFindingsAndFix(t, "load-on-top", `
 foo()
 load(":f.bzl", "x")
@@ -418,3 +430,61 @@ d.update(
 		[]string{},
 		scopeEverywhere)
 }
+
+func TestTrailingComma(t *testing.T) {
+	checkFindingsAndFix(t, "trailing-comma", `
+foo(
+    name = "foo",
+    srcs = [
+        "a",
+        "b"
+    ],
+    env = {
+        "a": "b"
+    }
+)`, `
+foo(
+    name = "foo",
+    srcs = [
+        "a",
+        "b",
+    ],
+    env = {
+        "a": "b",
+    },
+)`,
+		[]string{
+			":1: The last element of a multi-line list, dict or call should be followed by a trailing comma.",
+			":3: The last element of a multi-line list, dict or call should be followed by a trailing comma.",
+			":7: The last element of a multi-line list, dict or call should be followed by a trailing comma.",
+		},
+		scopeEverywhere)
+
+	checkFindings(t, "trailing-comma", `
+foo(
+    name = "foo",
+    srcs = [
+        "a",
+        "b",
+    ],
+    deps = [":bar", ":baz"],
+    data = glob([
+        "*.txt",
+    ]),
+)`,
+		[]string{},
+		scopeEverywhere)
+
+	checkFindings(t, "trailing-comma", `
+def macro(name, *args, **kwargs):
+    foo(
+        name = name,
+        *args
+    )
+    bar(
+        name = name,
+        **kwargs
+    )`,
+		[]string{},
+		scopeBzl)
+}
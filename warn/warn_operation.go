@@ -120,3 +120,51 @@ func integerDivisionWarning(f *build.File, fix bool) []*Finding {
 	})
 	return findings
 }
+
+// isEmptyLiteral checks whether an expression is an empty list or dict literal without comments.
+func isEmptyLiteral(expr build.Expr) bool {
+	switch expr := expr.(type) {
+	case *build.ListExpr:
+		return len(expr.List) == 0 && !hasComments(expr) && !hasComments(&expr.End)
+	case *build.DictExpr:
+		return len(expr.List) == 0 && !hasComments(expr) && !hasComments(&expr.End)
+	}
+	return false
+}
+
+// hasComments checks whether a node has any comments attached to it.
+func hasComments(expr build.Expr) bool {
+	comments := expr.Comment()
+	return len(comments.Before) > 0 || len(comments.Suffix) > 0 || len(comments.After) > 0
+}
+
+func redundantConcatWarning(f *build.File) []*LinterFinding {
+	var findings []*LinterFinding
+	build.WalkPointers(f, func(expr *build.Expr, stack []build.Expr) {
+		binary, ok := (*expr).(*build.BinaryExpr)
+		if !ok || binary.Op != "+" {
+			return
+		}
+		var operand build.Expr
+		switch {
+		case isEmptyLiteral(binary.Y):
+			operand = binary.X
+		case isEmptyLiteral(binary.X):
+			operand = binary.Y
+		default:
+			return
+		}
+		if f.Type != build.TypeBuild {
+			// In .bzl files `x = BASE + []` may be intended to create a mutable copy of BASE,
+			// replacing it with `x = BASE` would make x an alias.
+			findings = append(findings, makeLinterFinding(binary,
+				"Concatenation with an empty list or dict has no effect other than copying the value. "+
+					"Use list() or dict() if a copy is needed."))
+			return
+		}
+		findings = append(findings, makeLinterFinding(binary,
+			"Concatenation with an empty list or dict has no effect.",
+			LinterReplacement{Old: expr, New: operand}))
+	})
+	return findings
+}
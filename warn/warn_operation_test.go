@@ -1,6 +1,10 @@
 package warn
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/bazelbuild/buildtools/build"
+)
 
 func TestIntegerDivision(t *testing.T) {
 	checkFindingsAndFix(t, "integer-division", `
@@ -79,3 +83,52 @@ for x in l:
 		},
 		scopeEverywhere)
 }
+
+func TestRedundantConcat(t *testing.T) {
+	// The fix is only applied in BUILD files, in other files the concatenation
+	// may be intended to make a mutable copy.
+	input := `
+a = BASE + []
+b = [] + BASE
+c = BASE + {}
+d = BASE + [x]
+e = BASE + [  # comment
+]
+f = "a" + "b"
+g = BASE + [] + OTHER
+`
+	compareFindings(t, "redundant-concat", input,
+		[]string{
+			":1: Concatenation with an empty list or dict has no effect.",
+			":2: Concatenation with an empty list or dict has no effect.",
+			":3: Concatenation with an empty list or dict has no effect.",
+			":8: Concatenation with an empty list or dict has no effect.",
+		},
+		scopeBuild, build.TypeBuild)
+	checkFix(t, "redundant-concat", input, `
+a = BASE
+b = BASE
+c = BASE
+d = BASE + [x]
+e = BASE + [  # comment
+]
+f = "a" + "b"
+g = BASE + OTHER
+`,
+		scopeBuild, build.TypeBuild)
+
+	input = `
+def f():
+    x = BASE + []
+    x.append("foo")
+    return x
+`
+	for _, fileType := range []build.FileType{build.TypeBzl, build.TypeDefault, build.TypeWorkspace} {
+		compareFindings(t, "redundant-concat", input,
+			[]string{
+				":2: Concatenation with an empty list or dict has no effect other than copying the value. Use list() or dict() if a copy is needed.",
+			},
+			fileType, fileType)
+		checkFix(t, "redundant-concat", input, input, fileType, fileType)
+	}
+}
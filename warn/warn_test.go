@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/bazelbuild/buildtools/build"
+	"github.com/bazelbuild/buildtools/edit"
 	"github.com/bazelbuild/buildtools/testutils"
 )
 
@@ -114,3 +115,163 @@ func checkFindingsAndFix(t *testing.T, category, input, output string, expected
 		checkFix(t, category, output, output, scope, fileType)
 	}
 }
+
+func TestBuildozerFix(t *testing.T) {
+	RegisterBuildozerFixes()
+
+	input := `load(":foo.bzl", "unused")
+
+native.cc_library(
+    name = "foo",
+    visibility = ["//visibility:private"],
+)
+`
+	expected := `load(":foo.bzl", "unused")
+
+cc_library(
+    name = "foo",
+    visibility = ["//visibility:private"],
+)
+`
+	f, err := build.Parse("package/BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := edit.CmdEnvironment{File: f, Rule: f.Rules("")[0], Pkg: "package", Args: []string{"native-build"}}
+	result, err := edit.AllCommands["fix"].Fn(edit.NewOpts(), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil {
+		t.Fatal("fix native-build: the file hasn't been changed")
+	}
+	if have := build.Format(result); !bytes.Equal(have, []byte(expected)) {
+		t.Error("fix native-build: diff (-expected, +ours)")
+		testutils.Tdiff(t, []byte(expected), have)
+	}
+
+	// Nothing left to fix
+	if result, err := edit.AllCommands["fix"].Fn(edit.NewOpts(), env); err != nil || result != nil {
+		t.Errorf("fix native-build applied twice: got (%v, %v), want (nil, nil)", result, err)
+	}
+}
+
+func TestRunCategories(t *testing.T) {
+	input := `load(":foo.bzl", "unused")
+
+native.cc_library(
+    name = "foo",
+    visibility = ["//visibility:private"],
+)
+`
+	f, err := build.Parse("package/BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings := FileWarnings(f, "package", []string{"load", "native-build"}, false); len(findings) != 2 {
+		t.Fatalf("got %d findings for all categories, want 2", len(findings))
+	}
+
+	findings := RunCategories(f, "package", []string{"native-build", "native-build", "unknown-category"}, false)
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+	if findings[0].Category != "native-build" {
+		t.Errorf("got a finding of category %q, want %q", findings[0].Category, "native-build")
+	}
+
+	// Fixing one category leaves the findings of other categories intact.
+	if findings := RunCategories(f, "package", []string{"native-build"}, true); len(findings) != 0 {
+		t.Errorf("got %d findings after fixing, want 0", len(findings))
+	}
+	if findings := RunCategories(f, "package", []string{"load"}, false); len(findings) != 1 {
+		t.Errorf("got %d findings for the load category, want 1", len(findings))
+	}
+
+	// The package isn't derived from the path of the file.
+	f, err = build.ParseBuild("/home/user/workspace/foo/BUILD", []byte(`py_binary(
+    name = "x",
+    srcs = ["//foo:main.py"],
+    main = "main.py",
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings := RunCategories(f, "foo", []string{"py-main-in-srcs"}, true); len(findings) != 0 {
+		t.Errorf("got %d py-main-in-srcs findings, want 0", len(findings))
+	}
+	if got := f.Rules("")[0].AttrStrings("srcs"); len(got) != 1 {
+		t.Errorf("got srcs %v, want them unchanged", got)
+	}
+}
+
+func TestApplyFix(t *testing.T) {
+	input := `a = BASE + []
+
+b = [] + OTHER
+`
+	f, err := build.ParseBuild("package/BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := FileWarnings(f, "package", []string{"redundant-concat"}, false)
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	// Only the second concatenation is fixed.
+	if !findings[1].HasFix() {
+		t.Fatalf("finding %q has no fix", findings[1].Message)
+	}
+
+	other, err := build.ParseBuild("package/BUILD", []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ApplyFix(other, findings[1]) {
+		t.Error("ApplyFix() applied a fix to a different file")
+	}
+
+	if !ApplyFix(f, findings[1]) {
+		t.Fatal("ApplyFix() = false, want true")
+	}
+	want := `a = BASE + []
+
+b = OTHER
+`
+	if got := string(build.Format(f)); got != want {
+		t.Errorf("got file after ApplyFix():\n%s\nwant:\n%s", got, want)
+	}
+	if findings[1].HasFix() || ApplyFix(f, findings[1]) {
+		t.Error("the fix of the finding can be applied twice")
+	}
+
+	// Findings of the warnings without automatic fixes can't be fixed.
+	f, err = build.ParseBuild("package/BUILD", []byte(`cc_library(srcs = glob(["foo.cc"]))`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings = FileWarnings(f, "package", []string{"constant-glob"}, false)
+	if len(findings) != 1 {
+		t.Fatalf("got %d constant-glob findings, want 1", len(findings))
+	}
+	if findings[0].HasFix() || ApplyFix(f, findings[0]) {
+		t.Error("ApplyFix() applied a fix for a constant-glob finding")
+	}
+
+	// Legacy warnings can only be fixed all at once, even if they have automatic fixes.
+	f, err = build.ParseBuild("package/BUILD", []byte(`native.cc_library(name = "foo")`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings = FileWarnings(f, "package", []string{"native-build"}, false)
+	if len(findings) != 1 {
+		t.Fatalf("got %d native-build findings, want 1", len(findings))
+	}
+	if findings[0].HasFix() || ApplyFix(f, findings[0]) {
+		t.Error("ApplyFix() applied a fix for a native-build finding")
+	}
+	if findings := RunCategories(f, "package", []string{"native-build"}, true); len(findings) != 0 {
+		t.Errorf("got %d native-build findings after fixing, want 0", len(findings))
+	}
+}